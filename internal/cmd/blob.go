@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type blobOptions struct {
+	signOptions
+	sigstoreOptions
+	outFileOptions
+	BlobPath string
+}
+
+// Validates the options in context with arguments
+func (bo *blobOptions) Validate() error {
+	errs := append([]error{},
+		bo.signOptions.Validate(),
+		bo.outFileOptions.Validate(),
+		bo.sigstoreOptions.Validate(),
+	)
+
+	if bo.BlobPath == "" {
+		errs = append(errs, errors.New("path to the file to sign is empty"))
+	}
+
+	if !bo.Sign {
+		errs = append(errs, errors.New("blobs are always signed, --sign=false is not supported"))
+	}
+	return errors.Join(errs...)
+}
+
+func (bo *blobOptions) AddFlags(cmd *cobra.Command) {
+	bo.signOptions.AddFlags(cmd)
+	bo.outFileOptions.AddFlags(cmd)
+	bo.sigstoreOptions.AddFlags(cmd)
+
+	cmd.PersistentFlags().StringVarP(
+		&bo.BlobPath, "file", "f", "", "path to the file to sign",
+	)
+}
+
+func addBlob(parentCmd *cobra.Command) {
+	opts := &blobOptions{}
+	blobCmd := &cobra.Command{
+		Short: "signs a file into a bundle with a message signature",
+		Long: fmt.Sprintf(`
+🥨 %s blob: Sign arbitrary files
+
+The blob subcommand signs any file, the resulting bundle holds a message
+signature over the file digest instead of a DSSE envelope. To verify the
+bundle, pass the signed file to the verify subcommand using --artifact.
+`, appname),
+		Use: "blob",
+		Example: fmt.Sprintf(`
+Sign a file and write the bundle:
+
+  %s blob --out=release.tar.gz.bundle.json release.tar.gz
+
+Verify the bundle against the file:
+
+  %s verify --artifact=release.tar.gz release.tar.gz.bundle.json
+
+`, appname, appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 && opts.BlobPath != "" {
+				return errors.New("file path specified twice (positional argument and flag)")
+			}
+			if len(args) > 0 {
+				opts.BlobPath = args[0]
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("validating options: %w", err)
+			}
+
+			data, err := os.ReadFile(opts.BlobPath)
+			if err != nil {
+				return fmt.Errorf("reading file: %w", err)
+			}
+
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := signer.SignBlobContext(cmd.Context(), data)
+			if err != nil {
+				return fmt.Errorf("signing file: %w", err)
+			}
+
+			o, closer, err := opts.OutputWriter()
+			if err != nil {
+				return fmt.Errorf("getting output stream: %w", err)
+			}
+			defer closer()
+
+			return signer.WriteBundle(bundle, o)
+		},
+	}
+	opts.AddFlags(blobCmd)
+	parentCmd.AddCommand(blobCmd)
+}
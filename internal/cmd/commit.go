@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/release-utils/util"
 
 	"github.com/carabiner-dev/bnd/internal/git"
+	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
 // commitOptions
@@ -156,7 +157,7 @@ Same, but cloning the repo from a local clone:
 			}
 			return nil
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// Validate the options
 			if err := opts.Validate(); err != nil {
 				return err
@@ -247,16 +248,29 @@ Same, but cloning the repo from a local clone:
 			statement.AddSubject(subject)
 
 			// Marshal the attestation data
-			attData, err := statement.ToJson()
+			attData, err := marshalStatement(statement)
 			if err != nil {
 				return fmt.Errorf("marshaling statement json: %w", err)
 			}
 
 			logrus.Debugf("ATTESTATION:\n%s\n/ATTESTATION\n", string(attData))
 
-			signer := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			// When not signing, output the statement as is
+			if !opts.Sign {
+				o, closer, err := opts.OutputWriter()
+				if err != nil {
+					return fmt.Errorf("getting output stream: %w", err)
+				}
+				defer closer()
+				return opts.writeUnsigned(attData, bnd.InTotoPayloadType, o)
+			}
+
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
 
-			bundle, err := signer.SignStatement(attData)
+			bundle, err := signer.SignStatementContext(cmd.Context(), attData)
 			if err != nil {
 				return fmt.Errorf("writing signing statement: %w", err)
 			}
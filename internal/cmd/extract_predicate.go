@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/carabiner-dev/ampel/pkg/attestation"
 	"github.com/spf13/cobra"
 
 	"github.com/carabiner-dev/bnd/pkg/bundle"
@@ -100,6 +101,10 @@ func addExtractPredicate(parentCmd *cobra.Command) {
 				return nil
 			}
 
+			if b.GetStatement() == nil {
+				return writeRawPayload(out, tool, b)
+			}
+
 			pred, err := tool.ExtractPredicate(b)
 			if err != nil {
 				return fmt.Errorf("extracting predicate: %w", err)
@@ -112,12 +117,25 @@ func addExtractPredicate(parentCmd *cobra.Command) {
 	parentCmd.AddCommand(extractCmd)
 }
 
+// writeRawPayload writes the DSSE payload of a bundle that does not wrap
+// an in-toto statement.
+func writeRawPayload(out io.Writer, tool *bundle.Tool, envelope attestation.Envelope) error {
+	_, payload, err := tool.ExtractPayload(envelope)
+	if err != nil {
+		return fmt.Errorf("extracting payload: %w", err)
+	}
+	if _, err := out.Write(payload); err != nil {
+		return fmt.Errorf("writing payload: %w", err)
+	}
+	return nil
+}
+
 func encodeOutputJSON(out io.Writer, data any) error {
 	enc := json.NewEncoder(out)
 	enc.SetIndent("", "  ")
 	enc.SetEscapeHTML(false)
 	if err := enc.Encode(data); err != nil {
-		return fmt.Errorf("encoding predicate: %w", err)
+		return fmt.Errorf("encoding json output: %w", err)
 	}
 	return nil
 }
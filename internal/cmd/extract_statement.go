@@ -81,6 +81,11 @@ func addExtractAttestation(parentCmd *cobra.Command) {
 			}
 			defer ocloser()
 
+			// Payloads that are not statements are written as is
+			if pred == nil {
+				return writeRawPayload(out, tool, b)
+			}
+
 			enc := json.NewEncoder(out)
 			enc.SetIndent("", "  ")
 			enc.SetEscapeHTML(false)
@@ -4,6 +4,8 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -12,8 +14,11 @@ import (
 	"github.com/carabiner-dev/ampel/pkg/attestation"
 	ampelb "github.com/carabiner-dev/ampel/pkg/formats/envelope/bundle"
 	"github.com/carabiner-dev/jsonl"
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/carabiner-dev/bnd/pkg/bnd"
 	"github.com/carabiner-dev/bnd/pkg/bundle"
 )
 
@@ -96,10 +101,23 @@ data about the bundle.
 }
 
 func printEnvelopeDetails(reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+
+	// Bundles with message signatures don't carry an attestation, so
+	// we print their details here.
+	pb := &protobundle.Bundle{}
+	if err := protojson.Unmarshal(data, pb); err == nil && pb.GetMessageSignature() != nil {
+		printMessageSignatureDetails(pb)
+		return nil
+	}
+
 	tool := bundle.NewTool()
 
 	// Parse the bundle JSON
-	envelope, err := tool.ParseBundle(reader)
+	envelope, err := tool.ParseBundle(bytes.NewReader(data))
 	if err != nil {
 		if errors.Is(err, attestation.ErrNotCorrectFormat) {
 			fmt.Printf("⚠️  JSON data is not a known envelope format\n\n")
@@ -151,9 +169,23 @@ func printEnvelopeDetails(reader io.Reader) error {
 		} else {
 			fmt.Println("⚠️ Attestation has no subjects")
 		}
+	} else if payloadType, payload, err := tool.ExtractPayload(envelope); err == nil {
+		fmt.Println("📦 Payload Details:")
+		fmt.Printf("   Payload Type: %s\n", payloadType)
+		fmt.Printf("   Payload Size: %d bytes\n", len(payload))
 	} else {
 		fmt.Println("⚠️ No attestation found in envelope")
 	}
 	fmt.Println("")
 	return nil
 }
+
+// printMessageSignatureDetails prints the details of a bundle signing a blob
+func printMessageSignatureDetails(pb *protobundle.Bundle) {
+	digest := pb.GetMessageSignature().GetMessageDigest()
+	fmt.Printf("✉️  Envelope Media Type: %s\n", pb.GetMediaType())
+	fmt.Printf("🔏 Signer identity: [not yet implemented]\n")
+	fmt.Println("📄 Message Signature Details:")
+	fmt.Printf("   Signed digest: %s:%s\n", bnd.HashAlgorithmName(digest.GetAlgorithm()), hex.EncodeToString(digest.GetDigest()))
+	fmt.Println("")
+}
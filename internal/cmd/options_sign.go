@@ -4,25 +4,186 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/carabiner-dev/bnd/internal/sts"
 	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
+// keyPassphraseEnvVar is the environment variable read to get the
+// passphrase of encrypted signing keys.
+const keyPassphraseEnvVar = "BND_KEY_PASSPHRASE"
+
+// identityTokenEnvVar is the environment variable read to get an OIDC
+// identity token to sign with.
+const identityTokenEnvVar = "BND_IDENTITY_TOKEN"
+
 type signOptions struct {
 	Sign            bool
+	DSSE            bool
+	SkipValidation  bool
+	Timestamp       bool
+	AppendToRekor   bool
 	OidcRedirectURL string
 	OidcIssuer      string
 	OidcClientID    string
+	KeyPath         string
+	BundleVersion   string
+
+	IdentityToken      string
+	IdentityTokenFile  string
+	STSProviders       []string
+	STSContinueOnError bool
+
+	FulcioURL             string
+	RekorURL              string
+	TimestampAuthorityURL string
+	SigningConfigPath     string
+	SigningConfigFromTUF  bool
+	changed               func(string) bool
 }
 
 func (so *signOptions) Validate() error {
+	if so.DSSE && so.Sign {
+		return errors.New("--dsse can only be used when not signing (--sign=false)")
+	}
+	if so.KeyPath != "" {
+		if _, err := os.Stat(so.KeyPath); err != nil {
+			return fmt.Errorf("checking signing key: %w", err)
+		}
+	}
+	if so.BundleVersion != "" && !slices.Contains(bnd.BundleVersions, so.BundleVersion) {
+		return fmt.Errorf("invalid --bundle-version %q (supported: %s)", so.BundleVersion, strings.Join(bnd.BundleVersions, ", "))
+	}
+	if so.SigningConfigPath != "" && so.SigningConfigFromTUF {
+		return errors.New("--signing-config and --signing-config-tuf are mutually exclusive")
+	}
+	if so.IdentityToken != "" && so.IdentityTokenFile != "" {
+		return errors.New("--identity-token and --identity-token-file are mutually exclusive")
+	}
+	if so.KeyPath != "" && (so.IdentityToken != "" || so.IdentityTokenFile != "") {
+		return errors.New("identity tokens cannot be used when signing with a key")
+	}
+	if so.IdentityTokenFile != "" {
+		if _, err := os.Stat(so.IdentityTokenFile); err != nil {
+			return fmt.Errorf("checking identity token file: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeUnsigned writes a statement without signing it. The statement is
+// validated (unless disabled) and, if requested, wrapped in an unsigned DSSE
+// envelope of the specified payload type.
+func (so *signOptions) writeUnsigned(data []byte, payloadType string, w io.Writer) error {
+	signer := bnd.NewSigner()
+	signer.Options.SkipValidation = so.SkipValidation
+	signer.Options.PayloadType = payloadType
+	prepared, err := signer.PrepareStatement(data)
+	if err != nil {
+		return fmt.Errorf("checking statement: %w", err)
+	}
+
+	if so.DSSE {
+		data, err = marshalEnvelope(prepared.Envelope)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("writing statement: %w", err)
+	}
 	return nil
 }
 
+// marshalEnvelope returns the JSON encoding of a DSSE envelope. The
+// signatures list is always written, even when empty.
+func marshalEnvelope(envelope *dsse.Envelope) ([]byte, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling envelope: %w", err)
+	}
+	return data, nil
+}
+
+// readIdentityToken returns the identity token passed in the command line,
+// read from a file or set in the environment. If none is set, it returns
+// an empty string.
+func (so *signOptions) readIdentityToken() (string, error) {
+	switch {
+	case so.IdentityToken != "":
+		return so.IdentityToken, nil
+	case so.IdentityTokenFile != "":
+		data, err := os.ReadFile(so.IdentityTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading identity token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return os.Getenv(identityTokenEnvVar), nil
+	}
+}
+
+// applyInstance sets the instance signing settings in the options not
+// explicitly set in the command line.
+func (so *signOptions) applyInstance(instance *bnd.Instance) {
+	for flag, v := range map[string]struct {
+		target *string
+		value  string
+	}{
+		"oidc-issuer":    {&so.OidcIssuer, instance.OidcIssuer},
+		"oidc-client-id": {&so.OidcClientID, instance.OidcClientID},
+		"fulcio-url":     {&so.FulcioURL, instance.FulcioURL},
+		"rekor-url":      {&so.RekorURL, instance.RekorURL},
+		"timestamp-url":  {&so.TimestampAuthorityURL, instance.TimestampAuthorityURL},
+	} {
+		if !so.changed(flag) && v.value != "" {
+			*v.target = v.value
+		}
+	}
+
+	for flag, v := range map[string]struct {
+		target *bool
+		value  bool
+	}{
+		"timestamp":          {&so.Timestamp, instance.Timestamp},
+		"tlog":               {&so.AppendToRekor, instance.AppendToRekor},
+		"signing-config-tuf": {&so.SigningConfigFromTUF, instance.SigningConfigFromTUF},
+	} {
+		if !so.changed(flag) {
+			*v.target = v.value
+		}
+	}
+}
+
 func (so *signOptions) AddFlags(cmd *cobra.Command) {
+	so.changed = cmd.PersistentFlags().Changed
+
+	cmd.PersistentFlags().BoolVar(
+		&so.Sign, "sign", true, "trigger the signing process, when false the statement is written unsigned",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&so.DSSE, "dsse", false, "write the unsigned statement wrapped in a DSSE envelope (with --sign=false)",
+	)
+
 	cmd.PersistentFlags().BoolVar(
-		&so.Sign, "sign", true, "trigger the signing process",
+		&so.SkipValidation, "skip-validation", false, "sign without checking that the statement is a valid in-toto attestation",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.BundleVersion, "bundle-version", "",
+		fmt.Sprintf("version of the written bundles (%s), defaults to the version produced by the signer", strings.Join(bnd.BundleVersions, ", ")),
 	)
 
 	cmd.PersistentFlags().StringVar(
@@ -36,4 +197,59 @@ func (so *signOptions) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(
 		&so.OidcClientID, "oidc-client-id", bnd.DefaultSignerOptions.OidcClientID, "Client ID to to set in token audience",
 	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.KeyPath, "key", "",
+		fmt.Sprintf("sign with a private key instead of keyless signing (passphrase read from $%s)", keyPassphraseEnvVar),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.IdentityToken, "identity-token", "",
+		fmt.Sprintf("OIDC identity token to request the signing certificate (also read from $%s)", identityTokenEnvVar),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.IdentityTokenFile, "identity-token-file", "", "path to a file containing the OIDC identity token",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&so.STSProviders, "sts-provider", []string{},
+		fmt.Sprintf(
+			"ambient credentials providers to use, prefix with - to exclude or use %q to disable (available: %s)",
+			sts.ProviderNone, strings.Join(sts.ProviderNames(sts.DefaultProviders), ", "),
+		),
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&so.STSContinueOnError, "sts-continue-on-error", false,
+		"try the next ambient credentials provider when one fails instead of aborting",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&so.Timestamp, "timestamp", bnd.DefaultSignerOptions.Timestamp, "get a signed timestamp from the timestamp authority",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&so.AppendToRekor, "tlog", bnd.DefaultSignerOptions.AppendToRekor, "record the signature in the transparency log",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.FulcioURL, "fulcio-url", "", fmt.Sprintf("Fulcio instance to request certificates from (default %s)", bnd.DefaultFulcioURL),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.RekorURL, "rekor-url", "", fmt.Sprintf("Rekor transparency log to record signatures (default %s)", bnd.DefaultRekorURL),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.TimestampAuthorityURL, "timestamp-url", "", fmt.Sprintf("timestamp authority URL (default %s)", bnd.GitHubTimeStamperURL),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.SigningConfigPath, "signing-config", "", "path to a sigstore signing_config.json defining the services to use",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&so.SigningConfigFromTUF, "signing-config-tuf", false, "read the sigstore signing config from the TUF repository",
+	)
 }
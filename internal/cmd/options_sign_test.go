@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+func TestWriteUnsigned(t *testing.T) {
+	t.Parallel()
+	statement := []byte(sigstoretest.Statement)
+
+	for _, tc := range []struct {
+		name        string
+		data        []byte
+		payloadType string
+		opts        signOptions
+		mustErr     bool
+	}{
+		{"statement", statement, bnd.InTotoPayloadType, signOptions{}, false},
+		{"dsse", statement, bnd.InTotoPayloadType, signOptions{DSSE: true}, false},
+		{"dsse-payload-type", []byte(`{"a":1}`), "application/json", signOptions{DSSE: true}, false},
+		{"invalid", []byte(`{"a":1}`), bnd.InTotoPayloadType, signOptions{}, true},
+		{"skip-validation", []byte(`{"a":1}`), bnd.InTotoPayloadType, signOptions{SkipValidation: true}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			err := tc.opts.writeUnsigned(tc.data, tc.payloadType, &b)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if !tc.opts.DSSE {
+				require.Equal(t, tc.data, b.Bytes())
+				return
+			}
+			envelope := struct {
+				PayloadType string `json:"payloadType"`
+				Payload     []byte `json:"payload"`
+			}{}
+			require.NoError(t, json.Unmarshal(b.Bytes(), &envelope))
+			require.Equal(t, tc.payloadType, envelope.PayloadType)
+			require.Equal(t, tc.data, envelope.Payload)
+		})
+	}
+}
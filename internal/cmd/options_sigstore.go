@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -14,11 +15,27 @@ import (
 )
 
 type sigstoreOptions struct {
-	TufRootURL  string
-	TufRootPath string
+	TufRootURL     string
+	TufRootPath    string
+	Instance       string
+	InstanceConfig string
+	tufInitialRoot []byte
+	changed        func(string) bool
 }
 
 func (so *sigstoreOptions) AddFlags(cmd *cobra.Command) {
+	so.changed = cmd.PersistentFlags().Changed
+
+	cmd.PersistentFlags().StringVar(
+		&so.Instance, "instance", "",
+		fmt.Sprintf("sigstore instance profile to use (built-in: %s, %s, %s)", bnd.InstancePublicGood, bnd.InstanceStaging, bnd.InstanceGitHub),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.InstanceConfig, "instance-config", bnd.DefaultInstancesConfigPath(),
+		"path to a YAML file defining additional sigstore instance profiles",
+	)
+
 	cmd.PersistentFlags().StringVar(
 		&so.TufRootURL, "trust-root", bnd.SigstorePublicGoodBaseURL,
 		"Base URL to fetch the trusted TUF roots",
@@ -26,7 +43,7 @@ func (so *sigstoreOptions) AddFlags(cmd *cobra.Command) {
 
 	cmd.PersistentFlags().StringVar(
 		&so.TufRootPath, "trust-root-path", "",
-		"Path to an already downloaded TUF trust root JSON file",
+		"Path to an already downloaded trusted_root.json file (disables TUF updates)",
 	)
 }
 
@@ -37,5 +54,51 @@ func (so *sigstoreOptions) Validate() error {
 			errs = append(errs, fmt.Errorf("parsing tuf URL: %w", err))
 		}
 	}
+	if so.TufRootPath != "" {
+		if _, err := os.Stat(so.TufRootPath); err != nil {
+			errs = append(errs, fmt.Errorf("checking trusted root path: %w", err))
+		}
+	}
+	if so.InstanceConfig != "" && so.changed("instance-config") {
+		if _, err := os.Stat(so.InstanceConfig); err != nil {
+			errs = append(errs, fmt.Errorf("checking instances config: %w", err))
+		}
+	}
 	return errors.Join(errs...)
 }
+
+// getInstance returns the selected sigstore instance profile. If no
+// instance was selected, it returns nil.
+func (so *sigstoreOptions) getInstance() (*bnd.Instance, error) {
+	if so.Instance == "" {
+		return nil, nil
+	}
+	return bnd.GetInstance(so.Instance, so.InstanceConfig)
+}
+
+// applyInstance sets the trust settings of the instance in the options
+// not explicitly set in the command line.
+func (so *sigstoreOptions) applyInstance(instance *bnd.Instance) error {
+	initialRoot, err := instance.TufInitialRoot()
+	if err != nil {
+		return err
+	}
+
+	// The TUF settings come in pairs, if either was set we don't touch them.
+	if so.changed("trust-root") || so.changed("trust-root-path") {
+		return nil
+	}
+	so.TufRootURL = instance.TufRootURL
+	so.TufRootPath = instance.TrustedRootPath
+	so.tufInitialRoot = initialRoot
+	return nil
+}
+
+// tufOptions returns the TUF options set in the command line
+func (so *sigstoreOptions) tufOptions() bnd.TufOptions {
+	return bnd.TufOptions{
+		TufRootURL:     so.TufRootURL,
+		TufRootPath:    so.TufRootPath,
+		TufInitialRoot: so.tufInitialRoot,
+	}
+}
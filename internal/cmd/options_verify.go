@@ -2,22 +2,58 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
 
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
 	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
 type verifcationOptions struct {
-	RequireCTlog        bool
-	RequireTimestamp    bool
-	RequireTlog         bool
-	SkipIdentityCheck   bool
-	ExpectedIssuer      string
-	ExpectedIssuerRegex string
-	ExpectedSan         string
-	ExpectedSanRegex    string
+	RequireCTlog          bool
+	RequireTimestamp      bool
+	RequireTlog           bool
+	SkipIdentityCheck     bool
+	ExpectedIssuers       []string
+	ExpectedIssuerRegexes []string
+	ExpectedSans          []string
+	ExpectedSanRegexes    []string
+	ExpectedExtensions    certificate.Extensions
+	KeyPaths              []string
+	KeyDir                string
+	GitHubTrustRoot       bool
+	ArtifactPath          string
+	SubjectPaths          []string
+	SubjectDigests        []string
+	changed               func(string) bool
+}
+
+// applyInstance sets the instance verification defaults in the options not
+// explicitly set in the command line.
+func (vo *verifcationOptions) applyInstance(instance *bnd.Instance) {
+	for flag, v := range map[string]struct {
+		target *bool
+		value  bool
+	}{
+		"ctlog":      {&vo.RequireCTlog, instance.RequireCTlog},
+		"tlog":       {&vo.RequireTlog, instance.RequireTlog},
+		"timestamps": {&vo.RequireTimestamp, instance.RequireTimestamp},
+		"github":     {&vo.GitHubTrustRoot, instance.GitHubTrustRoot},
+	} {
+		if !vo.changed(flag) {
+			*v.target = v.value
+		}
+	}
 }
 
 func (vo *verifcationOptions) AddFlags(cmd *cobra.Command) {
+	vo.changed = cmd.PersistentFlags().Changed
+
 	cmd.PersistentFlags().BoolVar(
 		&vo.RequireCTlog, "ctlog", true,
 		"require and check RFC 3161 timestamps in the verified bundle",
@@ -38,34 +74,200 @@ func (vo *verifcationOptions) AddFlags(cmd *cobra.Command) {
 		"allow skipping identity verification",
 	)
 
-	cmd.PersistentFlags().StringVar(
-		&vo.ExpectedSan, "identity", "",
-		"expected certificate identity (SAN)",
+	cmd.PersistentFlags().StringArrayVar(
+		&vo.ExpectedSans, "identity", []string{},
+		"expected certificate identity (SAN) (can be repeated)",
 	)
 
-	cmd.PersistentFlags().StringVar(
-		&vo.ExpectedSanRegex, "identity-regex", "",
-		"regex to check the certificate identity (SAN)",
+	cmd.PersistentFlags().StringArrayVar(
+		&vo.ExpectedSanRegexes, "identity-regex", []string{},
+		"regex to check the certificate identity (SAN) (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringArrayVar(
+		&vo.ExpectedIssuers, "issuer", []string{},
+		"expected OIDC issuer for the certificate identity (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringArrayVar(
+		&vo.ExpectedIssuerRegexes, "issuer-regex", []string{},
+		"regex to check the certificate's OIDC identity issuer (can be repeated)",
+	)
+
+	// Flags to check the Fulcio certificate extensions
+	for _, ext := range []struct {
+		target *string
+		name   string
+		help   string
+	}{
+		{&vo.ExpectedExtensions.SourceRepositoryURI, "source-repository-uri", "source repository URL the build was based on"},
+		{&vo.ExpectedExtensions.SourceRepositoryDigest, "source-repository-digest", "commit digest of the source code the build was based on"},
+		{&vo.ExpectedExtensions.SourceRepositoryRef, "source-repository-ref", "source repository ref the build was based on (eg refs/heads/main)"},
+		{&vo.ExpectedExtensions.SourceRepositoryIdentifier, "source-repository-identifier", "immutable identifier of the source repository"},
+		{&vo.ExpectedExtensions.SourceRepositoryOwnerURI, "source-repository-owner-uri", "URL of the owner of the source repository"},
+		{&vo.ExpectedExtensions.SourceRepositoryOwnerIdentifier, "source-repository-owner-identifier", "immutable identifier of the source repository owner"},
+		{&vo.ExpectedExtensions.SourceRepositoryVisibilityAtSigning, "source-repository-visibility", "source repository visibility at signing time (public, private)"},
+		{&vo.ExpectedExtensions.BuildConfigURI, "build-config-uri", "URL of the top-level build instructions (eg the workflow file)"},
+		{&vo.ExpectedExtensions.BuildConfigDigest, "build-config-digest", "digest of the top-level build instructions"},
+		{&vo.ExpectedExtensions.BuildSignerURI, "build-signer-uri", "URL of the build instructions that signed (eg a reusable workflow)"},
+		{&vo.ExpectedExtensions.BuildSignerDigest, "build-signer-digest", "digest of the build instructions that signed"},
+		{&vo.ExpectedExtensions.BuildTrigger, "build-trigger", "event that triggered the build (eg push)"},
+		{&vo.ExpectedExtensions.RunnerEnvironment, "runner-environment", "build runner environment (github-hosted, self-hosted)"},
+		{&vo.ExpectedExtensions.RunInvocationURI, "run-invocation-uri", "URL of the build run"},
+	} {
+		cmd.PersistentFlags().StringVar(ext.target, ext.name, "", "expected signer certificate extension: "+ext.help)
+	}
+
+	cmd.PersistentFlags().StringSliceVar(
+		&vo.KeyPaths, "key", []string{},
+		"path to a public key to verify key-signed bundles (can be repeated)",
 	)
 
 	cmd.PersistentFlags().StringVar(
-		&vo.ExpectedIssuer, "issuer", "",
-		"expected OIDC issuer for the certificate identity",
+		&vo.KeyDir, "key-dir", "",
+		"directory with public keys (.pub, .pem) to verify key-signed bundles",
 	)
 
 	cmd.PersistentFlags().StringVar(
-		&vo.ExpectedIssuerRegex, "issuer-regex", "",
-		"regex to check the certificate's OIDC identity issuer",
+		&vo.ArtifactPath, "artifact", "",
+		"path to the signed file to check against the bundle (this, --subject-file or --subject-digest is required to verify blob bundles)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&vo.SubjectPaths, "subject-file", []string{},
+		"path to an artifact that must match a subject of the statement (can be repeated)",
+	)
+
+	cmd.PersistentFlags().StringSliceVar(
+		&vo.SubjectDigests, "subject-digest", []string{},
+		"digest (algo:hex) that must match a subject of the statement (can be repeated)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&vo.GitHubTrustRoot, "github", false,
+		"verify against GitHub's trusted root (attestations from private repositories)",
 	)
 }
 
 func (vo *verifcationOptions) Validate() error {
 	errs := []error{}
-	if vo.ExpectedIssuer != "" && vo.ExpectedIssuerRegex != "" {
-		errs = append(errs, errors.New("only one of issuer or issuer-regexp can be set at the same"))
+	sans := len(vo.ExpectedSans) + len(vo.ExpectedSanRegexes)
+	issuers := len(vo.ExpectedIssuers) + len(vo.ExpectedIssuerRegexes)
+	switch {
+	case sans == 0 && issuers == 0:
+	case sans == 0:
+		errs = append(errs, errors.New("issuers require an identity or identity-regex to check"))
+	case issuers == 0:
+		errs = append(errs, errors.New("identities require an issuer or issuer-regex to check"))
+	case issuers != 1 && issuers != sans:
+		errs = append(errs, fmt.Errorf(
+			"%d issuers defined for %d identities, set one issuer or one per identity", issuers, sans,
+		))
+	}
+	if (len(vo.KeyPaths) > 0 || vo.KeyDir != "") && (sans > 0 || issuers > 0) {
+		errs = append(errs, errors.New("identity and issuer checks cannot be used when verifying with keys"))
+	}
+	if vo.ExpectedExtensions != (certificate.Extensions{}) && (len(vo.KeyPaths) > 0 || vo.KeyDir != "") {
+		errs = append(errs, errors.New("certificate extensions cannot be checked when verifying with keys"))
+	}
+	if vo.GitHubTrustRoot && (len(vo.KeyPaths) > 0 || vo.KeyDir != "") {
+		errs = append(errs, errors.New("GitHub trust root mode cannot be used when verifying with keys"))
+	}
+	for _, path := range vo.SubjectPaths {
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("checking subject file: %w", err))
+		}
 	}
-	if vo.ExpectedSan != "" && vo.ExpectedSanRegex != "" {
-		errs = append(errs, errors.New("only one of identity or identity-regexp can be set at the same"))
+	if hashRegex == nil {
+		hashRegex = regexp.MustCompile(hashRegexStr)
+	}
+	for _, d := range vo.SubjectDigests {
+		if !hashRegex.MatchString(d) {
+			errs = append(errs, fmt.Errorf("invalid subject digest: %q", d))
+		}
 	}
 	return errors.Join(errs...)
 }
+
+// expectedIdentities pairs the identity and issuer flags into the accepted
+// signer identities. Identities are followed by the identity regexes and
+// issuers by the issuer regexes, a single issuer applies to all identities.
+func (vo *verifcationOptions) expectedIdentities() []bnd.ExpectedIdentity {
+	issuers := []bnd.ExpectedIdentity{}
+	for _, i := range vo.ExpectedIssuers {
+		issuers = append(issuers, bnd.ExpectedIdentity{Issuer: i})
+	}
+	for _, i := range vo.ExpectedIssuerRegexes {
+		issuers = append(issuers, bnd.ExpectedIdentity{IssuerRegex: i})
+	}
+
+	ret := []bnd.ExpectedIdentity{}
+	for _, san := range vo.ExpectedSans {
+		ret = append(ret, bnd.ExpectedIdentity{San: san})
+	}
+	for _, re := range vo.ExpectedSanRegexes {
+		ret = append(ret, bnd.ExpectedIdentity{SanRegex: re})
+	}
+	if len(issuers) == 0 {
+		return ret
+	}
+	for i := range ret {
+		issuer := issuers[0]
+		if len(issuers) > 1 {
+			issuer = issuers[i]
+		}
+		ret[i].Issuer, ret[i].IssuerRegex = issuer.Issuer, issuer.IssuerRegex
+	}
+	return ret
+}
+
+// setMessageArtifact sets the artifact to check a message signature against
+// from the subject flags. Message signatures have no statement subjects, so
+// a single subject file or digest identifies the signed artifact.
+func (vo *verifcationOptions) setMessageArtifact(opts *bnd.VerificationOptions) error {
+	if vo.ArtifactPath != "" || !vo.matchesSubjects() {
+		return nil
+	}
+	if len(vo.SubjectPaths)+len(vo.SubjectDigests) != 1 {
+		return errors.New("message signatures are verified against a single subject file or digest")
+	}
+	if len(vo.SubjectPaths) == 1 {
+		opts.ArtifactPath = vo.SubjectPaths[0]
+		return nil
+	}
+	algo, value, _ := strings.Cut(vo.SubjectDigests[0], ":")
+	opts.ArtifactDigestAlgo, opts.ArtifactDigest = algo, value
+	return nil
+}
+
+// matchesSubjects returns true if the options define artifacts to match
+// against the statement subjects.
+func (vo *verifcationOptions) matchesSubjects() bool {
+	return len(vo.SubjectPaths) > 0 || len(vo.SubjectDigests) > 0
+}
+
+// matchSubjects matches the subject files and digests to the subjects of
+// the verified statement. If any of the artifacts does not match a subject,
+// the match results are returned along with an error.
+func (vo *verifcationOptions) matchSubjects(result *verify.VerificationResult) (*bnd.SubjectMatchResult, error) {
+	if result.Statement == nil {
+		return nil, fmt.Errorf("%w: bundle has no in-toto statement", bnd.ErrSubjectMismatch)
+	}
+
+	artifacts, err := bnd.HashArtifacts(vo.SubjectPaths)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range vo.SubjectDigests {
+		algo, value, _ := strings.Cut(d, ":")
+		artifacts = append(artifacts, bnd.Artifact{Name: d, Digest: map[string]string{algo: value}})
+	}
+
+	match := bnd.MatchSubjects(result.Statement.GetSubject(), artifacts)
+	if !match.Matched() {
+		return match, fmt.Errorf(
+			"%w: no subject matches %s", bnd.ErrSubjectMismatch, strings.Join(match.UnmatchedArtifacts, ", "),
+		)
+	}
+	return match, nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+func TestExpectedIdentities(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name       string
+		opts       verifcationOptions
+		mustErr    bool
+		identities []bnd.ExpectedIdentity
+	}{
+		{"none", verifcationOptions{}, false, []bnd.ExpectedIdentity{}},
+		{
+			"single",
+			verifcationOptions{ExpectedSans: []string{"a@example.com"}, ExpectedIssuers: []string{"https://a"}},
+			false, []bnd.ExpectedIdentity{{San: "a@example.com", Issuer: "https://a"}},
+		},
+		{
+			"shared-issuer",
+			verifcationOptions{
+				ExpectedSans: []string{"a@example.com"}, ExpectedSanRegexes: []string{"@example\\.org$"},
+				ExpectedIssuerRegexes: []string{"^https://"},
+			},
+			false, []bnd.ExpectedIdentity{
+				{San: "a@example.com", IssuerRegex: "^https://"},
+				{SanRegex: "@example\\.org$", IssuerRegex: "^https://"},
+			},
+		},
+		{
+			"paired",
+			verifcationOptions{
+				ExpectedSans: []string{"a@example.com", "b@example.com"}, ExpectedIssuers: []string{"https://a"},
+				ExpectedIssuerRegexes: []string{"^https://b"},
+			},
+			false, []bnd.ExpectedIdentity{
+				{San: "a@example.com", Issuer: "https://a"},
+				{San: "b@example.com", IssuerRegex: "^https://b"},
+			},
+		},
+		{"no-issuer", verifcationOptions{ExpectedSans: []string{"a@example.com"}}, true, nil},
+		{"no-identity", verifcationOptions{ExpectedIssuers: []string{"https://a"}}, true, nil},
+		{
+			"issuer-count",
+			verifcationOptions{
+				ExpectedSans: []string{"a", "b", "c"}, ExpectedIssuers: []string{"https://a", "https://b"},
+			},
+			true, nil,
+		},
+		{
+			"keys",
+			verifcationOptions{
+				ExpectedSans: []string{"a"}, ExpectedIssuers: []string{"https://a"}, KeyPaths: []string{"key.pub"},
+			},
+			true, nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.opts.Validate()
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.identities, tc.opts.expectedIdentities())
+		})
+	}
+}
@@ -19,7 +19,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 	"sigs.k8s.io/release-utils/util"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
 type predicateOptions struct {
@@ -140,7 +144,7 @@ func addPredicate(parentCmd *cobra.Command) {
 			opts.SubjectValues = vals
 			return nil
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// Validate the options
 			if err := opts.Validate(); err != nil {
 				return err
@@ -208,16 +212,29 @@ func addPredicate(parentCmd *cobra.Command) {
 			statement.Subject = append(statement.Subject, hashes.ToResourceDescriptors()...)
 
 			// Marshal the attestation data
-			attData, err := statement.ToJson()
+			attData, err := marshalStatement(statement)
 			if err != nil {
 				return fmt.Errorf("marshaling statement json: %w", err)
 			}
 
 			logrus.Debugf("ATTESTATION:\n%s\n/ATTESTATION\n", string(attData))
 
-			signer := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			// When not signing, output the statement as is
+			if !opts.Sign {
+				o, closer, err := opts.OutputWriter()
+				if err != nil {
+					return fmt.Errorf("getting output stream: %w", err)
+				}
+				defer closer()
+				return opts.writeUnsigned(attData, bnd.InTotoPayloadType, o)
+			}
 
-			bundle, err := signer.SignStatement(attData)
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := signer.SignStatementContext(cmd.Context(), attData)
 			if err != nil {
 				return fmt.Errorf("writing signing statement: %w", err)
 			}
@@ -251,3 +268,22 @@ func convertYaml(in []byte) ([]byte, error) {
 	}
 	return jsondata, nil
 }
+
+// marshalStatement returns the in-toto JSON encoding of a statement. The
+// statement ToJson method encodes the embedded protobuf structs with
+// encoding/json, which does not follow the in-toto field names, so the
+// statement is converted to its protobuf form and serialized with protojson.
+func marshalStatement(statement *intoto.Statement) ([]byte, error) {
+	s := &v1.Statement{
+		Type:          statement.Type,
+		Subject:       statement.Subject,
+		PredicateType: string(statement.PredicateType),
+	}
+	if statement.Predicate != nil {
+		s.Predicate = &structpb.Struct{}
+		if err := protojson.Unmarshal(statement.Predicate.GetData(), s.Predicate); err != nil {
+			return nil, fmt.Errorf("decoding predicate: %w", err)
+		}
+	}
+	return protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(s)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/carabiner-dev/ampel/pkg/formats/predicate"
+	"github.com/carabiner-dev/ampel/pkg/formats/statement/intoto"
+	v1 "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+func TestMarshalStatement(t *testing.T) {
+	t.Parallel()
+	pred, err := predicate.Parsers.Parse([]byte(`{"a": 1}`))
+	require.NoError(t, err)
+
+	statement := intoto.NewStatement(intoto.WithPredicate(pred))
+	statement.PredicateType = "https://example.com/v1"
+	statement.Subject = append(statement.Subject, &v1.ResourceDescriptor{
+		Name:             "test",
+		DownloadLocation: "https://example.com/test",
+		Digest:           map[string]string{"sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	})
+
+	data, err := marshalStatement(statement)
+	require.NoError(t, err)
+	require.NoError(t, bnd.ValidateStatement(data))
+
+	fields := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, v1.StatementTypeUri, fields["_type"])
+	require.NotContains(t, fields, "type")
+	require.Equal(t, map[string]any{"a": float64(1)}, fields["predicate"])
+
+	subjects, ok := fields["subject"].([]any)
+	require.True(t, ok)
+	require.Len(t, subjects, 1)
+	require.Contains(t, subjects[0], "downloadLocation")
+}
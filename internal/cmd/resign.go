@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/bnd/pkg/bundle"
+)
+
+type resignOptions struct {
+	signOptions
+	sigstoreOptions
+	outFileOptions
+	bundleOptions
+	Pair bool
+}
+
+// Validates the options in context with arguments
+func (ro *resignOptions) Validate() error {
+	errs := append([]error{},
+		ro.signOptions.Validate(),
+		ro.outFileOptions.Validate(),
+		ro.sigstoreOptions.Validate(),
+		ro.bundleOptions.Validate(),
+	)
+
+	if !ro.Sign {
+		errs = append(errs, errors.New("bundles are always re-signed, --sign=false is not supported"))
+	}
+	return errors.Join(errs...)
+}
+
+func (ro *resignOptions) AddFlags(cmd *cobra.Command) {
+	ro.signOptions.AddFlags(cmd)
+	ro.outFileOptions.AddFlags(cmd)
+	ro.sigstoreOptions.AddFlags(cmd)
+	ro.bundleOptions.AddFlags(cmd)
+
+	cmd.PersistentFlags().BoolVar(
+		&ro.Pair, "pair", false,
+		"write the original and the new bundle together as jsonl",
+	)
+}
+
+func addResign(parentCmd *cobra.Command) {
+	opts := &resignOptions{}
+	resignCmd := &cobra.Command{
+		Short: "signs the statement of an existing bundle with a new identity",
+		Long: fmt.Sprintf(`
+🥨 %s resign: Counter-sign a bundle
+
+The resign subcommand extracts the DSSE payload from an existing bundle and
+signs it again with a new identity, producing a second bundle over the exact
+same statement. Use --pair to write both bundles into a jsonl file so they
+travel together.
+`, appname),
+		Use: "resign",
+		Example: fmt.Sprintf(`
+Counter-sign the statement in a bundle signed by CI:
+
+  %s resign --out=release.countersigned.json release.bundle.json
+
+Write the original and the new bundle into a jsonl file:
+
+  %s resign --pair --out=release.bundles.jsonl release.bundle.json
+
+`, appname, appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return opts.SetBundlePath(args[0])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("validating options: %w", err)
+			}
+
+			data, err := opts.ReadBundle()
+			if err != nil {
+				return err
+			}
+
+			original := &protobundle.Bundle{}
+			if err := protojson.Unmarshal(data, original); err != nil {
+				return fmt.Errorf("parsing bundle: %w", err)
+			}
+
+			payload, err := bundle.NewTool().ExtractAttestationJSON(original)
+			if err != nil {
+				return fmt.Errorf("extracting payload: %w", err)
+			}
+
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
+			// Keep the payload type to sign the exact same envelope contents
+			signer.Options.PayloadType = original.GetDsseEnvelope().GetPayloadType()
+
+			resigned, err := signer.SignStatementContext(cmd.Context(), payload)
+			if err != nil {
+				return fmt.Errorf("signing statement: %w", err)
+			}
+
+			o, closer, err := opts.OutputWriter()
+			if err != nil {
+				return fmt.Errorf("getting output stream: %w", err)
+			}
+			defer closer()
+
+			if !opts.Pair {
+				return signer.WriteBundle(resigned, o)
+			}
+
+			// The original bundle is written as it was, only the new bundle
+			// is converted to the requested bundle version.
+			originalJSON, err := protojson.Marshal(original)
+			if err != nil {
+				return fmt.Errorf("marshaling original bundle: %w", err)
+			}
+			if _, err := o.Write(append(originalJSON, '\n')); err != nil {
+				return fmt.Errorf("writing bundle: %w", err)
+			}
+			if err := signer.WriteBundle(resigned, o); err != nil {
+				return err
+			}
+			if _, err := o.Write([]byte("\n")); err != nil {
+				return fmt.Errorf("writing bundle: %w", err)
+			}
+			return nil
+		},
+	}
+	opts.AddFlags(resignCmd)
+	parentCmd.AddCommand(resignCmd)
+}
@@ -4,7 +4,11 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -68,6 +72,8 @@ func Execute() {
 		"log-level", "info", fmt.Sprintf("the logging verbosity, either %s", log.LevelNames()),
 	)
 	addStatement(rootCmd)
+	addBlob(rootCmd)
+	addResign(rootCmd)
 	addPredicate(rootCmd)
 	addExtract(rootCmd)
 	addInspect(rootCmd)
@@ -78,19 +84,87 @@ func Execute() {
 	addCommit(rootCmd)
 	rootCmd.AddCommand(version.WithFont("doom"))
 
-	if err := rootCmd.Execute(); err != nil {
-		logrus.Fatal(err)
+	// Cancel any pending network operations when interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		code := 1
+		var ee *exitError
+		if errors.As(err, &ee) {
+			code = ee.code
+		}
+		logrus.Error(err)
+		os.Exit(code)
 	}
 }
 
-// getSigner builds a bnd signer from a sigstore options set
-func getSigner(opts *sigstoreOptions, sopts *signOptions) *bnd.Signer {
+// exitError is an error that sets the exit code of the program
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// getSigner builds a bnd signer from a sigstore options set. If a sigstore
+// instance is selected, its settings are used for any options not set in
+// the command line.
+func getSigner(opts *sigstoreOptions, sopts *signOptions) (*bnd.Signer, error) {
+	instance, err := opts.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	if instance != nil {
+		if err := opts.applyInstance(instance); err != nil {
+			return nil, fmt.Errorf("applying instance settings: %w", err)
+		}
+		sopts.applyInstance(instance)
+	}
+
 	signer := bnd.NewSigner()
 	signer.Options.TufRootPath = opts.TufRootPath
 	signer.Options.TufRootURL = opts.TufRootURL
+	signer.Options.TufInitialRoot = opts.tufInitialRoot
 	signer.Options.OidcClientID = sopts.OidcClientID
 	signer.Options.OidcIssuer = sopts.OidcIssuer
 	signer.Options.OidcRedirectURL = sopts.OidcRedirectURL
+	signer.Options.Timestamp = sopts.Timestamp
+	signer.Options.AppendToRekor = sopts.AppendToRekor
+	signer.Options.KeyPath = sopts.KeyPath
+	signer.Options.SkipValidation = sopts.SkipValidation
+	signer.Options.BundleVersion = sopts.BundleVersion
+	signer.Options.FulcioURL = sopts.FulcioURL
+	signer.Options.RekorURL = sopts.RekorURL
+	signer.Options.TimestampAuthorityURL = sopts.TimestampAuthorityURL
+	signer.Options.SigningConfigPath = sopts.SigningConfigPath
+	signer.Options.SigningConfigFromTUF = sopts.SigningConfigFromTUF
+	signer.Options.STSProviders = sopts.STSProviders
+	signer.Options.STSContinueOnError = sopts.STSContinueOnError
+	if pass, ok := os.LookupEnv(keyPassphraseEnvVar); ok {
+		signer.Options.KeyPassphrase = []byte(pass)
+	}
+
+	// Identity tokens are only used in keyless signing
+	if sopts.KeyPath == "" {
+		raw, err := sopts.readIdentityToken()
+		if err != nil {
+			return nil, err
+		}
+		if raw != "" {
+			token, err := bnd.ParseIdentityToken(raw)
+			if err != nil {
+				return nil, err
+			}
+			signer.Options.Token = token
+		}
+	}
 
-	return signer
+	return signer, nil
 }
@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+const (
+	testIdentity = "signer@example.com"
+	testIssuer   = "https://issuer.example.com"
+)
+
+// runCommand executes a fresh command tree with the specified arguments
+func runCommand(t *testing.T, args ...string) error {
+	t.Helper()
+	cmd := &cobra.Command{Use: appname, SilenceUsage: true, SilenceErrors: true}
+	cmd.PersistentFlags().StringVar(&commandLineOpts.logLevel, "log-level", "info", "")
+	addStatement(cmd)
+	addPredicate(cmd)
+	addCommit(cmd)
+	addVerify(cmd)
+	addBlob(cmd)
+	addResign(cmd)
+	cmd.SetArgs(args)
+	return cmd.ExecuteContext(t.Context())
+}
+
+// initTestRepo creates a git repository with a single commit
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n"), 0o600))
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add("README.md")
+	require.NoError(t, err)
+	_, err = wt.Commit("Initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return dir
+}
+
+func TestCommandRoundTrip(t *testing.T) {
+	instance := sigstoretest.New(t)
+	trustedRoot := instance.TrustedRootPath(t)
+	token := ststest.Token(t, map[string]any{"email": testIdentity, "email_verified": true})
+
+	dir := t.TempDir()
+	statementPath := filepath.Join(dir, "statement.json")
+	require.NoError(t, os.WriteFile(statementPath, []byte(sigstoretest.Statement), 0o600))
+	predicatePath := filepath.Join(dir, "predicate.json")
+	require.NoError(t, os.WriteFile(predicatePath, []byte(`{"test": true}`), 0o600))
+	repoPath := initTestRepo(t)
+
+	signFlags := []string{
+		"--trust-root-path", trustedRoot,
+		"--fulcio-url", instance.FulcioURL,
+		"--rekor-url", instance.RekorURL,
+		"--timestamp-url", instance.TimestampAuthorityURL,
+		"--identity-token", token,
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{"statement", []string{"statement", statementPath}},
+		{"predicate", []string{
+			"predicate", "--type", "https://example.com/test",
+			"--subject", "sha256:" + strings.Repeat("b", 64), predicatePath,
+		}},
+		{"commit", []string{
+			"commit", "--repo", repoPath, "--type", "https://example.com/test",
+			"--predicate", predicatePath,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+			args := append(append(tc.args, signFlags...), "--out", bundlePath)
+			require.NoError(t, runCommand(t, args...))
+
+			verifyFlags := []string{"verify", "--trust-root-path", trustedRoot, "--ctlog=false", "--issuer", testIssuer}
+			require.NoError(t, runCommand(t, append(verifyFlags, "--identity", testIdentity, bundlePath)...))
+			err := runCommand(t, append(verifyFlags, "--identity", "other@example.com", bundlePath)...)
+			requireExitCode(t, 3, err)
+			require.NoError(t, runCommand(t, append(verifyFlags, "--identity", "other@example.com", "--identity", testIdentity, bundlePath)...))
+			err = runCommand(t, append(verifyFlags, "--identity", testIdentity, "--source-repository-uri", "https://github.com/example/repo", bundlePath)...)
+			requireExitCode(t, 3, err)
+		})
+	}
+
+	t.Run("missing-timestamps", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+		args := append([]string{"statement", "--tlog=false", "--timestamp=false", "--out", bundlePath, statementPath}, signFlags...)
+		require.NoError(t, runCommand(t, args...))
+		err := runCommand(t, "verify", "--trust-root-path", trustedRoot, "--ctlog=false", "--issuer", testIssuer, "--identity", testIdentity, bundlePath)
+		requireExitCode(t, 4, err)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		dir := t.TempDir()
+		stream := []byte{}
+		for i := range 3 {
+			bundlePath := filepath.Join(dir, fmt.Sprintf("bundle%d.json", i))
+			args := append([]string{"statement", "--out", bundlePath, statementPath}, signFlags...)
+			require.NoError(t, runCommand(t, args...))
+			data, err := os.ReadFile(bundlePath)
+			require.NoError(t, err)
+			stream = append(append(stream, bytes.TrimSpace(data)...), '\n')
+		}
+		streamPath := filepath.Join(dir, "bundles.jsonl")
+		require.NoError(t, os.WriteFile(streamPath, stream, 0o600))
+
+		verifyFlags := []string{"verify", "--trust-root-path", trustedRoot, "--ctlog=false", "--issuer", testIssuer, "--workers", "2"}
+		require.NoError(t, runCommand(t, append(verifyFlags, "--identity", testIdentity, streamPath)...))
+		err := runCommand(t, append(verifyFlags, "--identity", "other@example.com", streamPath)...)
+		requireExitCode(t, 3, err)
+	})
+}
+
+func TestResignRoundTrip(t *testing.T) {
+	instance := sigstoretest.New(t)
+	trustedRoot := instance.TrustedRootPath(t)
+	dir := t.TempDir()
+
+	statementPath := filepath.Join(dir, "statement.json")
+	require.NoError(t, os.WriteFile(statementPath, []byte(sigstoretest.Statement), 0o600))
+
+	signFlags := func(email string) []string {
+		return []string{
+			"--trust-root-path", trustedRoot,
+			"--fulcio-url", instance.FulcioURL,
+			"--rekor-url", instance.RekorURL,
+			"--timestamp-url", instance.TimestampAuthorityURL,
+			"--identity-token", ststest.Token(t, map[string]any{"email": email, "email_verified": true}),
+		}
+	}
+	verifyFlags := []string{"verify", "--trust-root-path", trustedRoot, "--ctlog=false", "--issuer", testIssuer}
+	const countersigner = "countersigner@example.com"
+
+	originalPath := filepath.Join(dir, "original.json")
+	require.NoError(t, runCommand(t, append([]string{"statement", "--out", originalPath, statementPath}, signFlags(testIdentity)...)...))
+
+	readEnvelope := func(t *testing.T, data []byte) *protobundle.Bundle {
+		t.Helper()
+		pb := &protobundle.Bundle{}
+		require.NoError(t, protojson.Unmarshal(data, pb))
+		require.NotNil(t, pb.GetDsseEnvelope())
+		return pb
+	}
+	originalData, err := os.ReadFile(originalPath)
+	require.NoError(t, err)
+	original := readEnvelope(t, originalData)
+
+	t.Run("resign", func(t *testing.T) {
+		resignedPath := filepath.Join(t.TempDir(), "resigned.json")
+		require.NoError(t, runCommand(t, append([]string{"resign", "--out", resignedPath, originalPath}, signFlags(countersigner)...)...))
+
+		data, err := os.ReadFile(resignedPath)
+		require.NoError(t, err)
+		resigned := readEnvelope(t, data)
+		require.Equal(t, original.GetDsseEnvelope().GetPayload(), resigned.GetDsseEnvelope().GetPayload())
+		require.Equal(t, original.GetDsseEnvelope().GetPayloadType(), resigned.GetDsseEnvelope().GetPayloadType())
+
+		require.NoError(t, runCommand(t, append(verifyFlags, "--identity", countersigner, resignedPath)...))
+		err = runCommand(t, append(verifyFlags, "--identity", testIdentity, resignedPath)...)
+		requireExitCode(t, 3, err)
+	})
+
+	t.Run("pair", func(t *testing.T) {
+		pairPath := filepath.Join(t.TempDir(), "pair.jsonl")
+		require.NoError(t, runCommand(t, append([]string{"resign", "--pair", "--out", pairPath, originalPath}, signFlags(countersigner)...)...))
+
+		data, err := os.ReadFile(pairPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2)
+		require.Equal(t, original.GetDsseEnvelope().GetPayload(), readEnvelope(t, []byte(lines[0])).GetDsseEnvelope().GetPayload())
+		require.Equal(t, original.GetDsseEnvelope().GetPayload(), readEnvelope(t, []byte(lines[1])).GetDsseEnvelope().GetPayload())
+
+		// Each line verifies with the identity of its signer
+		require.NoError(t, runCommand(t, append(verifyFlags, "--identity", testIdentity, "--identity", countersigner, pairPath)...))
+		err = runCommand(t, append(verifyFlags, "--identity", countersigner, pairPath)...)
+		requireExitCode(t, 3, err)
+	})
+
+	t.Run("pair-bundle-version", func(t *testing.T) {
+		pairPath := filepath.Join(t.TempDir(), "pair.jsonl")
+		require.NoError(t, runCommand(t, append([]string{
+			"resign", "--pair", "--bundle-version", bnd.BundleVersion02, "--out", pairPath, originalPath,
+		}, signFlags(countersigner)...)...))
+
+		data, err := os.ReadFile(pairPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2)
+
+		// Only the new bundle is converted, the original is kept as is
+		require.True(t, proto.Equal(original, readEnvelope(t, []byte(lines[0]))))
+		require.Equal(t, "application/vnd.dev.sigstore.bundle+json;version=0.2", readEnvelope(t, []byte(lines[1])).GetMediaType())
+		require.NoError(t, runCommand(t, append(verifyFlags, "--identity", testIdentity, "--identity", countersigner, pairPath)...))
+	})
+}
+
+// writeSigningKey writes a P-256 private key and its public key to dir
+func writeSigningKey(t *testing.T, dir string) (keyPath, pubPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	require.NoError(t, err)
+	pubPath = filepath.Join(dir, "key.pub")
+	require.NoError(t, os.WriteFile(pubPath, pubPEM, 0o600))
+	return keyPath, pubPath
+}
+
+// TestSignValidatedStatements checks that the statements built by predicate
+// and commit pass the statement validation when signing them.
+func TestSignValidatedStatements(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, pubPath := writeSigningKey(t, dir)
+
+	predicatePath := filepath.Join(dir, "predicate.json")
+	require.NoError(t, os.WriteFile(predicatePath, []byte(`{"test": true}`), 0o600))
+	repoPath := initTestRepo(t)
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{"predicate", []string{
+			"predicate", "--type", "https://example.com/test",
+			"--subject", "sha256:" + strings.Repeat("b", 64), predicatePath,
+		}},
+		{"commit", []string{
+			"commit", "--repo", repoPath, "--type", "https://example.com/test",
+			"--predicate", predicatePath,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+			args := append(tc.args, "--key", keyPath, "--tlog=false", "--timestamp=false", "--out", bundlePath)
+			require.NoError(t, runCommand(t, args...))
+
+			data, err := os.ReadFile(bundlePath)
+			require.NoError(t, err)
+			pb := &protobundle.Bundle{}
+			require.NoError(t, protojson.Unmarshal(data, pb))
+			require.NoError(t, bnd.ValidateStatement(pb.GetDsseEnvelope().GetPayload()))
+
+			require.NoError(t, runCommand(t, "verify", "--key", pubPath, "--tlog=false", "--timestamps=false", bundlePath))
+		})
+	}
+}
+
+func TestVerifyBlob(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, pubPath := writeSigningKey(t, dir)
+	blobPath := filepath.Join(dir, "blob.txt")
+	require.NoError(t, os.WriteFile(blobPath, []byte("Hello world\n"), 0o600))
+	bundlePath := filepath.Join(dir, "blob.bundle.json")
+	require.NoError(t, runCommand(t, "blob", "--key", keyPath, "--tlog=false", "--timestamp=false", "--out", bundlePath, blobPath))
+
+	sum := sha256.Sum256([]byte("Hello world\n"))
+	verifyFlags := []string{"verify", "--key", pubPath, "--tlog=false", "--timestamps=false"}
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		mustErr bool
+	}{
+		{"no-artifact", []string{}, true},
+		{"artifact", []string{"--artifact", blobPath}, false},
+		{"subject-file", []string{"--subject-file", blobPath}, false},
+		{"subject-digest", []string{"--subject-digest", "sha256:" + hex.EncodeToString(sum[:])}, false},
+		{"wrong-digest", []string{"--subject-digest", "sha256:" + strings.Repeat("0", 64)}, true},
+		{"two-digests", []string{"--subject-digest", "sha256:" + hex.EncodeToString(sum[:]), "--subject-file", blobPath}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := runCommand(t, append(append(verifyFlags, tc.args...), bundlePath)...)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func requireExitCode(t *testing.T, code int, err error) {
+	t.Helper()
+	var ee *exitError
+	require.ErrorAs(t, err, &ee)
+	require.Equal(t, code, ee.code)
+}
@@ -4,12 +4,18 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
 type statementOptions struct {
@@ -17,6 +23,9 @@ type statementOptions struct {
 	sigstoreOptions
 	outFileOptions
 	StatementPath string
+	BatchDir      string
+	OutDir        string
+	PayloadType   string
 }
 
 // Validates the options in context with arguments
@@ -27,8 +36,21 @@ func (so *statementOptions) Validate() error {
 		so.sigstoreOptions.Validate(),
 	)
 
-	if so.StatementPath == "" {
+	switch {
+	case so.BatchDir != "" && so.StatementPath != "":
+		errs = append(errs, errors.New("a statement path cannot be specified in batch mode"))
+	case so.BatchDir == "" && so.StatementPath == "":
 		errs = append(errs, errors.New("attestation path is empty"))
+	case so.BatchDir == "" && so.OutDir != "":
+		errs = append(errs, errors.New("an output directory can only be used in batch mode"))
+	}
+
+	if !so.Sign {
+		errs = append(errs, errors.New("statements are always signed, use \"statement prepare\" to get an unsigned envelope"))
+	}
+
+	if so.OutDir != "" && so.OutPath != "" {
+		errs = append(errs, errors.New("only one of --out or --out-dir can be set"))
 	}
 	return errors.Join(errs...)
 }
@@ -42,14 +64,39 @@ func (so *statementOptions) AddFlags(cmd *cobra.Command) {
 		&so.StatementPath, "statement", "s", "",
 		"Path to the in-toto statement file",
 	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.BatchDir, "batch", "",
+		"sign all the statements (*.json) in a directory with a single identity",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.PayloadType, "payload-type", bnd.InTotoPayloadType,
+		"DSSE payload type of the signed data, types other than in-toto are not validated as statements",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&so.OutDir, "out-dir", "",
+		"directory to write the bundles to in batch mode (default: packed jsonl to --out)",
+	)
 }
 
 func addStatement(parentCmd *cobra.Command) {
 	opts := &statementOptions{}
 	attCmd := &cobra.Command{
-		Short:             "binds an in-toto attestation in a signed bundle",
-		Use:               "statement",
-		Example:           fmt.Sprintf("%s statement file.intoto.json ", appname),
+		Short: "binds an in-toto attestation in a signed bundle",
+		Use:   "statement",
+		Example: fmt.Sprintf(`
+Sign a statement and write the bundle to a file:
+
+  %s statement --out=bundle.json file.intoto.json
+
+Sign all the statements in a directory obtaining the signer identity only once
+and pack the bundles into a jsonl file:
+
+  %s statement --batch=attestations/ --out=attestations.jsonl
+
+`, appname, appname),
 		SilenceUsage:      false,
 		SilenceErrors:     true,
 		PersistentPreRunE: initLogging,
@@ -62,11 +109,15 @@ func addStatement(parentCmd *cobra.Command) {
 			}
 			return nil
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := opts.Validate(); err != nil {
 				return fmt.Errorf("validating options: %w", err)
 			}
 
+			if opts.BatchDir != "" {
+				return runBatchSign(cmd.Context(), opts)
+			}
+
 			var f io.Reader
 			f, err := os.Open(opts.StatementPath)
 			if err != nil {
@@ -78,9 +129,13 @@ func addStatement(parentCmd *cobra.Command) {
 				return fmt.Errorf("reading statement data: %w", err)
 			}
 
-			signer := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
+			signer.Options.PayloadType = opts.PayloadType
 
-			bundle, err := signer.SignStatement(attData)
+			bundle, err := signer.SignStatementContext(cmd.Context(), attData)
 			if err != nil {
 				return fmt.Errorf("writing signing statement: %w", err)
 			}
@@ -98,5 +153,77 @@ func addStatement(parentCmd *cobra.Command) {
 		},
 	}
 	opts.AddFlags(attCmd)
+	addStatementPrepare(attCmd, opts)
+	addStatementAttach(attCmd, opts)
 	parentCmd.AddCommand(attCmd)
 }
+
+// runBatchSign signs all the statements in the batch directory and writes
+// the bundles to the output directory or as jsonl to the output stream.
+func runBatchSign(ctx context.Context, opts *statementOptions) error {
+	paths, err := filepath.Glob(filepath.Join(opts.BatchDir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("listing statements: %w", err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no statements (*.json) found in %s", opts.BatchDir)
+	}
+	slices.Sort(paths)
+
+	statements := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading statement data: %w", err)
+		}
+		statements = append(statements, data)
+	}
+
+	signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+	if err != nil {
+		return err
+	}
+	signer.Options.PayloadType = opts.PayloadType
+
+	bundles, err := signer.SignStatementsContext(ctx, statements)
+	if err != nil {
+		return fmt.Errorf("signing statements: %w", err)
+	}
+
+	// Write the bundles as files to the output directory
+	if opts.OutDir != "" {
+		if err := os.MkdirAll(opts.OutDir, os.FileMode(0o755)); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		for i, bundle := range bundles {
+			name := strings.TrimSuffix(filepath.Base(paths[i]), ".json") + ".bundle.json"
+			f, err := os.Create(filepath.Join(opts.OutDir, name))
+			if err != nil {
+				return fmt.Errorf("creating bundle file: %w", err)
+			}
+			err = signer.WriteBundle(bundle, f)
+			f.Close() //nolint:errcheck,gosec
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// ... or pack them into a jsonl stream
+	o, closer, err := opts.OutputWriter()
+	if err != nil {
+		return fmt.Errorf("getting output stream: %w", err)
+	}
+	defer closer()
+
+	for _, bundle := range bundles {
+		if err := signer.WriteBundle(bundle, o); err != nil {
+			return err
+		}
+		if _, err := o.Write([]byte("\n")); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	return nil
+}
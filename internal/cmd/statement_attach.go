@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+type statementAttachOptions struct {
+	*statementOptions
+	EnvelopePath    string
+	SignaturePath   string
+	CertificatePath string
+	PublicKeyPath   string
+}
+
+// Validates the options in context with arguments
+func (o *statementAttachOptions) Validate() error {
+	errs := []error{
+		o.outFileOptions.Validate(),
+		o.sigstoreOptions.Validate(),
+	}
+
+	if o.EnvelopePath == "" {
+		errs = append(errs, errors.New("envelope path is empty"))
+	}
+
+	if o.SignaturePath == "" {
+		errs = append(errs, errors.New("signature path is empty"))
+	}
+
+	switch {
+	case o.CertificatePath != "" && o.PublicKeyPath != "":
+		errs = append(errs, errors.New("only one of --certificate or --public-key can be set"))
+	case o.CertificatePath == "" && o.PublicKeyPath == "":
+		errs = append(errs, errors.New("a certificate or public key is required to attach the signature"))
+	}
+
+	if o.KeyPath != "" {
+		errs = append(errs, errors.New("--key cannot be used when attaching a signature, use --public-key"))
+	}
+	return errors.Join(errs...)
+}
+
+func (o *statementAttachOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&o.EnvelopePath, "envelope", "", "path to the unsigned DSSE envelope created by prepare",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.SignaturePath, "signature", "", "path to the signature of the PAE (raw or base64 encoded)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.CertificatePath, "certificate", "", "path to the signing certificate (PEM or DER)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.PublicKeyPath, "public-key", "", "path to the public key to verify the signature (PEM)",
+	)
+}
+
+// readSignature reads the signature file. Base64 encoded signatures are
+// decoded, anything else is treated as the raw signature.
+func readSignature(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature: %w", err)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data))); err == nil && len(decoded) > 0 {
+		return decoded, nil
+	}
+	return data, nil
+}
+
+func addStatementAttach(parentCmd *cobra.Command, parentOpts *statementOptions) {
+	opts := &statementAttachOptions{statementOptions: parentOpts}
+	attachCmd := &cobra.Command{
+		Short: "assembles a bundle from a prepared statement and its signature",
+		Long: `Assembles a sigstore bundle from an envelope created by "statement prepare"
+and the signature of its PAE computed out of band.
+
+The signature must be verifiable with the signing certificate or the public
+key, both are checked before writing the bundle. Timestamps and transparency
+log entries are added as when signing (see --timestamp and --tlog).
+`,
+		Use: "attach",
+		Example: fmt.Sprintf(`
+Attach a signature made with an offline key:
+
+  %s statement attach --signature=statement.sig --public-key=key.pub --out=bundle.json envelope.json
+
+`, appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 && opts.EnvelopePath != "" {
+				return errors.New("envelope path specified twice (positional argument and flag)")
+			}
+			if len(args) > 0 {
+				opts.EnvelopePath = args[0]
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("validating options: %w", err)
+			}
+
+			envelopeData, err := os.ReadFile(opts.EnvelopePath)
+			if err != nil {
+				return fmt.Errorf("reading envelope: %w", err)
+			}
+			envelope := &dsse.Envelope{}
+			if err := protojson.Unmarshal(envelopeData, envelope); err != nil {
+				return fmt.Errorf("parsing envelope: %w", err)
+			}
+
+			sig := &bnd.DetachedSignature{}
+			sig.Signature, err = readSignature(opts.SignaturePath)
+			if err != nil {
+				return err
+			}
+
+			if opts.CertificatePath != "" {
+				sig.Certificate, err = os.ReadFile(opts.CertificatePath)
+				if err != nil {
+					return fmt.Errorf("reading certificate: %w", err)
+				}
+			} else {
+				sig.PublicKey, err = os.ReadFile(opts.PublicKeyPath)
+				if err != nil {
+					return fmt.Errorf("reading public key: %w", err)
+				}
+			}
+
+			signer, err := getSigner(&opts.sigstoreOptions, &opts.signOptions)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := signer.AttachSignatureContext(cmd.Context(), envelope, sig)
+			if err != nil {
+				return fmt.Errorf("attaching signature: %w", err)
+			}
+
+			o, closer, err := opts.OutputWriter()
+			if err != nil {
+				return fmt.Errorf("getting output stream: %w", err)
+			}
+			defer closer()
+
+			return signer.WriteBundle(bundle, o)
+		},
+	}
+	opts.AddFlags(attachCmd)
+	parentCmd.AddCommand(attachCmd)
+}
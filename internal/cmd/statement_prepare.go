@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/carabiner-dev/bnd/pkg/bnd"
+)
+
+type statementPrepareOptions struct {
+	*statementOptions
+	PAEPath string
+}
+
+// Validates the options in context with arguments
+func (o *statementPrepareOptions) Validate() error {
+	errs := []error{o.outFileOptions.Validate()}
+	if o.StatementPath == "" {
+		errs = append(errs, errors.New("attestation path is empty"))
+	}
+	return errors.Join(errs...)
+}
+
+func (o *statementPrepareOptions) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&o.PAEPath, "pae", "",
+		"file to write the DSSE pre-authentication encoding (the data to sign) to",
+	)
+}
+
+func addStatementPrepare(parentCmd *cobra.Command, parentOpts *statementOptions) {
+	opts := &statementPrepareOptions{statementOptions: parentOpts}
+	prepareCmd := &cobra.Command{
+		Short: "prepares a statement to be signed out of band",
+		Long: `Wraps a statement in an unsigned DSSE envelope to sign it elsewhere.
+
+The envelope is written to --out (or STDOUT) and the pre-authentication
+encoding (PAE) of the envelope, the data that needs to be signed, is written
+to the file set in --pae. The SHA256 digest of the PAE is printed to STDERR
+for signers that operate on digests.
+
+Once signed, use "statement attach" to assemble the bundle.
+`,
+		Use: "prepare",
+		Example: fmt.Sprintf(`
+Prepare a statement and write the data to sign to a file:
+
+  %s statement prepare --pae=statement.pae --out=envelope.json statement.json
+
+`, appname),
+		SilenceUsage:      false,
+		SilenceErrors:     true,
+		PersistentPreRunE: initLogging,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 && opts.StatementPath != "" {
+				return errors.New("statement path specified twice (positional argument and flag)")
+			}
+			if len(args) > 0 {
+				opts.StatementPath = args[0]
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("validating options: %w", err)
+			}
+
+			attData, err := os.ReadFile(opts.StatementPath)
+			if err != nil {
+				return fmt.Errorf("reading statement data: %w", err)
+			}
+
+			signer := bnd.NewSigner()
+			signer.Options.SkipValidation = opts.SkipValidation
+			signer.Options.PayloadType = opts.PayloadType
+			prepared, err := signer.PrepareStatement(attData)
+			if err != nil {
+				return fmt.Errorf("preparing statement: %w", err)
+			}
+
+			envelopeJSON, err := marshalEnvelope(prepared.Envelope)
+			if err != nil {
+				return err
+			}
+
+			if opts.PAEPath != "" {
+				if err := os.WriteFile(opts.PAEPath, prepared.PAE, os.FileMode(0o644)); err != nil {
+					return fmt.Errorf("writing PAE: %w", err)
+				}
+			}
+
+			o, closer, err := opts.OutputWriter()
+			if err != nil {
+				return fmt.Errorf("getting output stream: %w", err)
+			}
+			defer closer()
+
+			if _, err := o.Write(envelopeJSON); err != nil {
+				return fmt.Errorf("writing envelope: %w", err)
+			}
+
+			fmt.Fprintf(os.Stderr, "PAE digest: sha256:%s\n", hex.EncodeToString(prepared.Digest())) //nolint:errcheck
+			return nil
+		},
+	}
+	opts.AddFlags(prepareCmd)
+	parentCmd.AddCommand(prepareCmd)
+}
@@ -4,27 +4,75 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/verify"
 	"github.com/spf13/cobra"
 
 	"github.com/carabiner-dev/bnd/pkg/bnd"
 )
 
+// Output formats of the verify subcommand
+const (
+	verifyFormatText = "text"
+	verifyFormatJSON = "json"
+)
+
+// verifyExitCodes maps the kinds of verification errors to the exit codes
+// of the verify subcommand. Any other errors exit with 1.
+var verifyExitCodes = map[string]int{
+	bnd.ErrorKindSignature:  2,
+	bnd.ErrorKindIdentity:   3,
+	bnd.ErrorKindTimestamps: 4,
+	bnd.ErrorKindIO:         5,
+	bnd.ErrorKindSubject:    6,
+	bnd.ErrorKindPolicy:     7,
+}
+
 type verifyOptions struct {
 	sigstoreOptions
 	verifcationOptions
 	bundleOptions
+	Format     string
+	PolicyPath string
+	Workers    int
+}
+
+// isStream returns true when the bundle path points to a jsonl file
+func (o *verifyOptions) isStream() bool {
+	return strings.HasSuffix(o.Path, ".jsonl")
 }
 
 // Validates the options in context with arguments
 func (o *verifyOptions) Validate() error {
-	return errors.Join(
+	errs := []error{
 		o.sigstoreOptions.Validate(),
 		o.verifcationOptions.Validate(),
 		o.bundleOptions.Validate(),
-	)
+	}
+	if o.Format != verifyFormatText && o.Format != verifyFormatJSON {
+		errs = append(errs, fmt.Errorf("invalid output format %q", o.Format))
+	}
+	if o.PolicyPath != "" {
+		if _, err := os.Stat(o.PolicyPath); err != nil {
+			errs = append(errs, fmt.Errorf("checking policy path: %w", err))
+		}
+	}
+	if o.Workers < 0 {
+		errs = append(errs, errors.New("the number of workers cannot be negative"))
+	}
+	if o.isStream() && (o.PolicyPath != "" || o.matchesSubjects() || o.ArtifactPath != "") {
+		errs = append(errs, errors.New("policies, subjects and artifacts are not supported when verifying jsonl files"))
+	}
+	return errors.Join(errs...)
 }
 
 // AddFlags adds the flags to the subcommand
@@ -32,13 +80,207 @@ func (o *verifyOptions) AddFlags(cmd *cobra.Command) {
 	o.verifcationOptions.AddFlags(cmd)
 	o.bundleOptions.AddFlags(cmd)
 	o.sigstoreOptions.AddFlags(cmd)
+
+	cmd.PersistentFlags().StringVar(
+		&o.Format, "format", verifyFormatText,
+		fmt.Sprintf("output format of the verification results (%s or %s)", verifyFormatText, verifyFormatJSON),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&o.PolicyPath, "policy", "",
+		"path to a YAML or JSON verification policy (policy settings override the flags)",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&o.Workers, "workers", 0,
+		"number of bundles verified in parallel in jsonl files (0 uses the number of CPUs)",
+	)
+}
+
+// verifyError wraps an error with the exit code of its verification
+// error kind.
+func verifyError(err error) error {
+	if code, ok := verifyExitCodes[bnd.ErrorKind(err)]; ok {
+		return &exitError{code: code, err: err}
+	}
+	return err
+}
+
+// writeVerifyReport writes the JSON report of a bundle verification
+func writeVerifyReport(
+	path string, result *verify.VerificationResult, match *bnd.SubjectMatchResult, rules []bnd.PolicyRuleResult, verr error,
+) error {
+	var bndl *bundle.Bundle
+	if result != nil {
+		// The bundle was already opened to verify it, so errors here are
+		// not expected. The report is written without log entries if any.
+		bndl, _ = bundle.LoadJSONFromPath(path) //nolint:errcheck
+	}
+	report := bnd.NewVerificationReport(bndl, result, verr)
+	report.Bundle = path
+	if match != nil {
+		report.SetSubjectMatch(match)
+	}
+	report.Policy = rules
+	return encodeOutputJSON(os.Stdout, report)
+}
+
+// verifyStream verifies all the bundles in a jsonl file and outputs the
+// results of each line. If any bundle fails, the command exits with the
+// code of the first failure.
+func verifyStream(ctx context.Context, verifier *bnd.Verifier, opts *verifyOptions) error {
+	reader, closer, err := opts.OpenBundle()
+	if err != nil {
+		return verifyError(err)
+	}
+	defer closer()
+
+	result, err := verifier.VerifyStreamContext(ctx, reader)
+	if err != nil {
+		return verifyError(fmt.Errorf("error verifying bundles: %w", err))
+	}
+
+	if opts.Format == verifyFormatJSON {
+		report := bnd.NewStreamReport(result)
+		report.Bundle = opts.Path
+		if err := encodeOutputJSON(os.Stdout, report); err != nil {
+			return err
+		}
+	} else {
+		printStreamResults(result)
+	}
+
+	for _, r := range result.Results {
+		if r.Error != nil {
+			return verifyError(fmt.Errorf(
+				"%d of %d bundles failed to verify, line %d: %w",
+				result.Failed(), len(result.Results), r.Line, r.Error,
+			))
+		}
+	}
+	return nil
+}
+
+// printStreamResults prints a table with the results of verifying the
+// bundles of a jsonl file followed by the aggregate result.
+func printStreamResults(result *bnd.StreamVerificationResult) {
+	fmt.Println("")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tRESULT\tSIGNER\tDETAILS") //nolint:errcheck
+	for _, r := range result.Results {
+		if r.Error != nil {
+			fmt.Fprintf(w, "%d\t❌ FAIL\t\t%s: %s\n", r.Line, bnd.ErrorKind(r.Error), r.Error) //nolint:errcheck
+			continue
+		}
+		signer, details := "", r.Verification.MediaType
+		if sig := r.Verification.Signature; sig != nil {
+			switch {
+			case sig.Certificate != nil:
+				signer = sig.Certificate.SubjectAlternativeName
+			case sig.PublicKeyID != nil:
+				signer = string(*sig.PublicKeyID)
+			}
+		}
+		if r.Verification.Statement != nil {
+			details = r.Verification.Statement.GetPredicateType()
+		}
+		fmt.Fprintf(w, "%d\t✅ OK\t%s\t%s\n", r.Line, signer, details) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck,gosec
+
+	fmt.Println("")
+	if result.Passed() {
+		fmt.Printf("✅ All %d bundles verified\n\n", len(result.Results))
+	} else {
+		fmt.Printf("❌ %d of %d bundles failed verification\n\n", result.Failed(), len(result.Results))
+	}
+}
+
+// isMessageSignature returns true if the bundle at path holds a message
+// signature instead of a DSSE envelope.
+func isMessageSignature(path string) bool {
+	bndl, err := bundle.LoadJSONFromPath(path)
+	return err == nil && bndl.GetMessageSignature() != nil
+}
+
+// printPolicyRules prints the results of the policy rules
+func printPolicyRules(rules []bnd.PolicyRuleResult) {
+	if len(rules) == 0 {
+		return
+	}
+	fmt.Println("Policy:")
+	for _, r := range rules {
+		if r.Passed {
+			fmt.Printf("  ✅ %s\n", r.Rule)
+		} else {
+			fmt.Printf("  ❌ %s: %s\n", r.Rule, r.Message)
+		}
+	}
+	fmt.Println("")
+}
+
+// printSubjectMatch prints the matches of local artifacts to the subjects
+func printSubjectMatch(match *bnd.SubjectMatchResult) {
+	if match == nil {
+		return
+	}
+	fmt.Println("Subjects:")
+	for _, s := range match.Subjects {
+		name := s.Subject.GetName()
+		if name == "" {
+			name = subjectDigestString(s.Subject.GetDigest())
+		}
+		if s.Matched() {
+			fmt.Printf("  ✅ %s (%s)\n", name, s.Artifact)
+		} else {
+			fmt.Printf("  ➖ %s\n", name)
+		}
+	}
+	for _, a := range match.UnmatchedArtifacts {
+		fmt.Printf("  ❌ %s matches no subject\n", a)
+	}
+	fmt.Println("")
+}
+
+// subjectDigestString returns a digest of a subject as algo:hex, preferring
+// sha256 digests.
+func subjectDigestString(digest map[string]string) string {
+	if v, ok := digest["sha256"]; ok {
+		return "sha256:" + v
+	}
+	algos := slices.Sorted(maps.Keys(digest))
+	if len(algos) == 0 {
+		return ""
+	}
+	return algos[0] + ":" + digest[algos[0]]
 }
 
 // addVerify adds the verification command
 func addVerify(parentCmd *cobra.Command) {
 	opts := &verifyOptions{}
 	verifyCmd := &cobra.Command{
-		Short:             "Verifies a bundle signature",
+		Short: "Verifies a bundle signature",
+		Long: fmt.Sprintf(`
+🥨 %s verify: Verify a bundle signature
+
+The verify subcommand checks the signature of a bundle, its transparency log
+entries and timestamps and the signer identity. With --format=json the
+verification results are written to STDOUT as a JSON report.
+
+When the bundle path ends in .jsonl (as written by %s pack) all the
+bundles in the file are verified in parallel and the result of each line is
+reported.
+
+When verification fails, %s verify exits with a code describing the failure:
+
+  2  the signature, certificate or log entries failed to verify
+  3  the signer identity does not match the expected identity
+  4  the bundle is missing the required log entries or timestamps
+  5  the bundle or the trusted material could not be read
+  6  the subject files or digests do not match the statement subjects
+  7  the bundle does not comply with the verification policy
+
+`, appname, appname, appname),
 		Use:               "verify",
 		Example:           fmt.Sprintf("%s verify bundle.json ", appname),
 		SilenceUsage:      false,
@@ -54,41 +296,108 @@ func addVerify(parentCmd *cobra.Command) {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := opts.Validate(); err != nil {
-				return err
+				return verifyError(err)
 			}
 
 			// Silence usage here as options are validated
 			cmd.SilenceUsage = true
 
+			instance, err := opts.getInstance()
+			if err != nil {
+				return err
+			}
+			if instance != nil {
+				if err := opts.sigstoreOptions.applyInstance(instance); err != nil {
+					return fmt.Errorf("applying instance settings: %w", err)
+				}
+				opts.verifcationOptions.applyInstance(instance)
+			}
+
 			verifier := bnd.NewVerifier()
 			verifier.Options = bnd.VerificationOptions{
-				TufOptions: bnd.TufOptions{
-					TufRootURL:  opts.TufRootURL,
-					TufRootPath: opts.TufRootPath,
-				},
-				RequireCTlog:        opts.RequireCTlog,
-				RequireTimestamp:    opts.RequireTimestamp,
-				RequireTlog:         opts.RequireTlog,
-				ExpectedIssuer:      opts.ExpectedIssuer,
-				ExpectedIssuerRegex: opts.ExpectedIssuerRegex,
-				ExpectedSan:         opts.ExpectedSan,
-				ExpectedSanRegex:    opts.ExpectedSanRegex,
-				SkipIdentityCheck:   opts.SkipIdentityCheck,
+				TufOptions:         opts.tufOptions(),
+				RequireCTlog:       opts.RequireCTlog,
+				RequireTimestamp:   opts.RequireTimestamp,
+				RequireTlog:        opts.RequireTlog,
+				ExpectedIdentities: opts.expectedIdentities(),
+				ExpectedExtensions: opts.ExpectedExtensions,
+				SkipIdentityCheck:  opts.SkipIdentityCheck,
+				KeyPaths:           opts.KeyPaths,
+				KeyDir:             opts.KeyDir,
+				GitHubTrustRoot:    opts.GitHubTrustRoot,
+				ArtifactPath:       opts.ArtifactPath,
+				StreamWorkers:      opts.Workers,
+			}
+
+			// jsonl files are verified bundle by bundle
+			if opts.isStream() {
+				return verifyStream(cmd.Context(), verifier, opts)
 			}
-			result, err := verifier.VerifyBundle(opts.Path)
+
+			// Message signatures are checked against the subject flags
+			// instead of matching them to statement subjects.
+			messageSignature := isMessageSignature(opts.Path)
+			if messageSignature {
+				if err := opts.setMessageArtifact(&verifier.Options); err != nil {
+					return verifyError(err)
+				}
+			}
+
+			var result *verify.VerificationResult
+			var rules []bnd.PolicyRuleResult
+			if opts.PolicyPath != "" {
+				policy, perr := bnd.LoadPolicy(opts.PolicyPath)
+				if perr != nil {
+					return verifyError(perr)
+				}
+				var presult *bnd.PolicyResult
+				presult, err = verifier.EvaluatePolicyContext(cmd.Context(), opts.Path, policy)
+				if presult != nil {
+					result, rules = presult.Verification, presult.Rules
+				}
+			} else {
+				result, err = verifier.VerifyBundleContext(cmd.Context(), opts.Path)
+			}
+
+			// Match the local artifacts to the verified statement subjects
+			var match *bnd.SubjectMatchResult
+			if err == nil && opts.matchesSubjects() && !messageSignature {
+				match, err = opts.matchSubjects(result)
+			}
+
+			if opts.Format == verifyFormatJSON {
+				if werr := writeVerifyReport(opts.Path, result, match, rules, err); werr != nil {
+					return werr
+				}
+				if err != nil {
+					return verifyError(fmt.Errorf("error verifying bundle: %w", err))
+				}
+				return nil
+			}
+
 			if err != nil {
 				fmt.Println("\n❌ Bundle Verification Failed")
 				fmt.Println("")
-				return fmt.Errorf("error verifying bundle: %w", err)
+				printPolicyRules(rules)
+				printSubjectMatch(match)
+				return verifyError(fmt.Errorf("error verifying bundle: %w", err))
 			}
 
 			fmt.Printf("\n✅ Bundle Verification OK!\n")
-			if !opts.SkipIdentityCheck {
+			switch {
+			case result.Signature != nil && result.Signature.PublicKeyID != nil:
+				fmt.Println("")
+				fmt.Printf("Signer key:  %s\n", string(*result.Signature.PublicKeyID))
+			case !opts.SkipIdentityCheck && result.VerifiedIdentity != nil && result.Signature != nil &&
+				result.Signature.Certificate != nil:
 				fmt.Println("")
-				fmt.Printf("Signer:      %+s\n", result.VerifiedIdentity.SubjectAlternativeName.SubjectAlternativeName)
-				fmt.Printf("OIDC Issuer: %+s\n", result.VerifiedIdentity.Issuer.Issuer)
+				fmt.Printf("Signer:      %+s\n", result.Signature.Certificate.SubjectAlternativeName)
+				fmt.Printf("OIDC Issuer: %+s\n", result.Signature.Certificate.Issuer)
+				fmt.Printf("Matched:     %s\n", bnd.MatchedIdentity(result))
 			}
 			fmt.Println("")
+			printPolicyRules(rules)
+			printSubjectMatch(match)
 			return nil
 		},
 	}
@@ -0,0 +1,388 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstoretest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer"
+	"github.com/digitorus/timestamp"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/sigstore/rekor/pkg/generated/models"
+	"github.com/sigstore/rekor/pkg/types"
+	rekorutil "github.com/sigstore/rekor/pkg/util"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/require"
+	"github.com/transparency-dev/merkle/rfc6962"
+
+	// Register the rekor entry types produced by sigstore-go
+	_ "github.com/sigstore/rekor/pkg/types/dsse/v0.0.1"
+	_ "github.com/sigstore/rekor/pkg/types/hashedrekord/v0.0.1"
+)
+
+// certificateValidity is the lifetime of the certificates issued by the CA
+const certificateValidity = 10 * time.Minute
+
+// githubServerURL prefixes the GitHub URLs in the certificate extensions
+const githubServerURL = "https://github.com"
+
+// handleSigningCert issues a certificate for the public key in the request
+// to the identity in the bearer token. The token is not verified.
+func (i *Instance) handleSigningCert(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		PublicKeyRequest struct {
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"publicKeyRequest"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey([]byte(req.PublicKeyRequest.PublicKey.Content))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	claims, err := tokenClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	template, err := leafTemplate(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, i.caCert, pub, i.caKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	chain := []string{
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: i.caCert.Raw})),
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"signedCertificateEmbeddedSct": map[string]any{
+			"chain": map[string]any{"certificates": chain},
+		},
+	})
+}
+
+// tokenClaims returns the claims of a JWT without verifying it
+func tokenClaims(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed identity token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("parsing token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// leafTemplate returns the template of a code signing certificate for the
+// identity in the token claims. As Fulcio does, email identities go in the
+// email SAN and any other subjects are set as URIs. GitHub Actions claims are
+// recorded in the CI extensions.
+func leafTemplate(claims map[string]any) (*x509.Certificate, error) {
+	issuer, _ := claims["iss"].(string) //nolint:errcheck
+	if issuer == "" {
+		return nil, fmt.Errorf("token has no issuer")
+	}
+
+	serial, err := rand.Int(rand.Reader, big128)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+
+	if email, ok := claims["email"].(string); ok && email != "" {
+		template.EmailAddresses = []string{email}
+	} else {
+		sub, _ := claims["sub"].(string) //nolint:errcheck
+		u, err := url.Parse(sub)
+		if err != nil || sub == "" {
+			return nil, fmt.Errorf("token subject is not a valid URI")
+		}
+		template.URIs = []*url.URL{u}
+	}
+
+	issuerV2, err := asn1.MarshalWithParams(issuer, "utf8")
+	if err != nil {
+		return nil, err
+	}
+	template.ExtraExtensions = []pkix.Extension{
+		{Id: certificate.OIDIssuer, Value: []byte(issuer)},
+		{Id: certificate.OIDIssuerV2, Value: issuerV2},
+	}
+
+	ciExtensions, err := githubExtensions(claims)
+	if err != nil {
+		return nil, err
+	}
+	template.ExtraExtensions = append(template.ExtraExtensions, ciExtensions...)
+	return template, nil
+}
+
+// githubExtensions returns the CI certificate extensions that Fulcio derives
+// from the claims of GitHub Actions tokens. Claims missing in the token are
+// skipped.
+func githubExtensions(claims map[string]any) ([]pkix.Extension, error) {
+	ret := []pkix.Extension{}
+	for _, ext := range []struct {
+		oid    asn1.ObjectIdentifier
+		claim  string
+		prefix string
+	}{
+		{certificate.OIDBuildSignerURI, "job_workflow_ref", githubServerURL + "/"},
+		{certificate.OIDBuildSignerDigest, "job_workflow_sha", ""},
+		{certificate.OIDRunnerEnvironment, "runner_environment", ""},
+		{certificate.OIDSourceRepositoryURI, "repository", githubServerURL + "/"},
+		{certificate.OIDSourceRepositoryDigest, "sha", ""},
+		{certificate.OIDSourceRepositoryRef, "ref", ""},
+		{certificate.OIDSourceRepositoryIdentifier, "repository_id", ""},
+		{certificate.OIDSourceRepositoryOwnerURI, "repository_owner", githubServerURL + "/"},
+		{certificate.OIDSourceRepositoryOwnerIdentifier, "repository_owner_id", ""},
+		{certificate.OIDBuildConfigURI, "workflow_ref", githubServerURL + "/"},
+		{certificate.OIDBuildConfigDigest, "workflow_sha", ""},
+		{certificate.OIDBuildTrigger, "event_name", ""},
+		{certificate.OIDSourceRepositoryVisibilityAtSigning, "repository_visibility", ""},
+	} {
+		value, _ := claims[ext.claim].(string) //nolint:errcheck
+		if value == "" {
+			continue
+		}
+		der, err := asn1.MarshalWithParams(ext.prefix+value, "utf8")
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pkix.Extension{Id: ext.oid, Value: der})
+	}
+	return ret, nil
+}
+
+// handleLogEntry appends the proposed entry to the log and returns it with
+// its signed entry timestamp and inclusion proof.
+func (i *Instance) handleLogEntry(w http.ResponseWriter, r *http.Request) {
+	proposed, err := models.UnmarshalProposedEntry(r.Body, runtime.JSONConsumer())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := types.CreateVersionedEntry(proposed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := types.CanonicalizeEntry(r.Context(), entry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leaf := rfc6962.DefaultHasher.HashLeaf(body)
+
+	i.mu.Lock()
+	i.leaves = append(i.leaves, leaf)
+	leaves := append([][]byte{}, i.leaves...)
+	i.mu.Unlock()
+
+	logIndex := int64(len(leaves) - 1)
+	treeSize := int64(len(leaves))
+	integratedTime := time.Now().Unix()
+	logID := hex.EncodeToString(i.logID)
+	encodedBody := base64.StdEncoding.EncodeToString(body)
+	rootHash := merkleRoot(leaves)
+
+	set, err := i.signEntryTimestamp(encodedBody, integratedTime, logIndex, logID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	signer, err := signature.LoadECDSASigner(i.rekorKey, crypto.SHA256)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	checkpoint, err := rekorutil.CreateAndSignCheckpoint(r.Context(), "rekor.sigstoretest", 1, uint64(treeSize), rootHash, signer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hashes := []string{}
+	for _, h := range inclusionProof(leaves, int(logIndex)) {
+		hashes = append(hashes, hex.EncodeToString(h))
+	}
+
+	uuid := hex.EncodeToString(leaf)
+	checkpointStr := string(checkpoint)
+	rootHashStr := hex.EncodeToString(rootHash)
+	w.Header().Set("ETag", uuid)
+	w.Header().Set("Location", "/api/v1/log/entries/"+uuid)
+	writeJSON(w, http.StatusCreated, models.LogEntry{
+		uuid: models.LogEntryAnon{
+			Body:           encodedBody,
+			IntegratedTime: &integratedTime,
+			LogID:          &logID,
+			LogIndex:       &logIndex,
+			Verification: &models.LogEntryAnonVerification{
+				InclusionProof: &models.InclusionProof{
+					Checkpoint: &checkpointStr,
+					Hashes:     hashes,
+					LogIndex:   &logIndex,
+					RootHash:   &rootHashStr,
+					TreeSize:   &treeSize,
+				},
+				SignedEntryTimestamp: strfmt.Base64(set),
+			},
+		},
+	})
+}
+
+// signEntryTimestamp signs the canonicalized entry data as Rekor does
+func (i *Instance) signEntryTimestamp(body string, integratedTime, logIndex int64, logID string) ([]byte, error) {
+	data, err := json.Marshal(map[string]any{
+		"body":           body,
+		"integratedTime": integratedTime,
+		"logIndex":       logIndex,
+		"logID":          logID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	canonicalized, err := jsoncanonicalizer.Transform(data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(canonicalized)
+	return ecdsa.SignASN1(rand.Reader, i.rekorKey, sum[:])
+}
+
+// merkleRoot computes the RFC 6962 root hash of the leaf hashes
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return rfc6962.DefaultHasher.HashChildren(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+// inclusionProof returns the RFC 6962 audit path of a leaf
+func inclusionProof(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if index < k {
+		return append(inclusionProof(leaves[:k], index), merkleRoot(leaves[k:]))
+	}
+	return append(inclusionProof(leaves[k:], index-k), merkleRoot(leaves[:k]))
+}
+
+// splitPoint returns the largest power of two smaller than n
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// handleTimestamp responds to an RFC 3161 timestamp request
+func (i *Instance) handleTimestamp(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := timestamp.ParseRequest(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	serial, err := rand.Int(rand.Reader, big128)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ts := timestamp.Timestamp{
+		HashAlgorithm:     req.HashAlgorithm,
+		HashedMessage:     req.HashedMessage,
+		Time:              time.Now(),
+		Nonce:             req.Nonce,
+		SerialNumber:      serial,
+		Policy:            asn1.ObjectIdentifier{1, 2, 3, 4, 1},
+		AddTSACertificate: req.Certificates,
+	}
+	resp, err := ts.CreateResponseWithOpts(i.tsaCert, i.tsaKey, crypto.SHA256)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp) //nolint:errcheck,gosec
+}
+
+// criticalTimestampingEKU returns a critical extended key usage extension
+// for timestamping.
+func criticalTimestampingEKU(t *testing.T) pkix.Extension {
+	t.Helper()
+	value, err := asn1.Marshal([]asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 8}})
+	require.NoError(t, err)
+	return pkix.Extension{Id: asn1.ObjectIdentifier{2, 5, 29, 37}, Critical: true, Value: value}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck,errchkjson
+}
@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sigstoretest runs an in-process fake sigstore instance to test
+// signing and verification without touching the network. The instance
+// serves a certificate authority issuing short-lived certificates (Fulcio),
+// a transparency log (Rekor), a timestamp authority and a TUF repository
+// publishing the trusted root of all of them.
+//
+// The fake services do not authenticate their clients: identity tokens are
+// not verified and certificates carry no SCTs, so verification must not
+// require certificate transparency.
+package sigstoretest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/stretchr/testify/require"
+)
+
+// big128 bounds the random certificate serial numbers
+var big128 = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// Statement is an in-toto statement to sign in tests
+const Statement = `{"_type":"https://in-toto.io/Statement/v1",` +
+	`"subject":[{"name":"file","digest":{"sha256":"` + "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" + `"}}],` +
+	`"predicateType":"https://example.com/test","predicate":{}}`
+
+// Instance is a fake sigstore instance served by an httptest server
+type Instance struct {
+	// URL is the base URL of the server, it is also the Fulcio URL
+	URL string
+
+	FulcioURL             string
+	RekorURL              string
+	TimestampAuthorityURL string
+	TufURL                string
+
+	// TufRoot is the root.json to bootstrap the TUF client
+	TufRoot []byte
+
+	// TrustedRoot is the trusted_root.json published in the TUF repository
+	TrustedRoot []byte
+
+	server *httptest.Server
+	start  time.Time
+
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+
+	rekorKey *ecdsa.PrivateKey
+	logID    []byte
+	mu       sync.Mutex
+	leaves   [][]byte
+
+	tsaKey  *ecdsa.PrivateKey
+	tsaCert *x509.Certificate
+	tsaRoot *x509.Certificate
+
+	tuf map[string][]byte
+}
+
+// New starts a fake sigstore instance. The server is shut down when the
+// test finishes.
+func New(t *testing.T) *Instance {
+	t.Helper()
+	i := &Instance{start: time.Now().Add(-time.Hour)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/signingCert", i.handleSigningCert)
+	mux.HandleFunc("POST /api/v1/log/entries", i.handleLogEntry)
+	mux.HandleFunc("POST /api/v1/timestamp", i.handleTimestamp)
+	mux.HandleFunc("GET /tuf/", i.handleTuf)
+	i.server = httptest.NewServer(mux)
+	t.Cleanup(i.server.Close)
+
+	i.URL = i.server.URL
+	i.FulcioURL = i.server.URL
+	i.RekorURL = i.server.URL
+	i.TimestampAuthorityURL = i.server.URL + "/api/v1/timestamp"
+	i.TufURL = i.server.URL + "/tuf"
+
+	i.initCA(t)
+	i.initRekor(t)
+	i.initTSA(t)
+	i.initTrustedRoot(t)
+	i.initTuf(t)
+	return i
+}
+
+// TrustedRootPath writes the trusted root to a temporary file and returns
+// its path.
+func (i *Instance) TrustedRootPath(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trusted_root.json")
+	require.NoError(t, os.WriteFile(path, i.TrustedRoot, 0o600))
+	return path
+}
+
+func (i *Instance) initCA(t *testing.T) {
+	t.Helper()
+	var err error
+	i.caKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	i.caCert = createCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "sigstoretest CA"},
+		NotBefore:             i.start,
+		NotAfter:              i.start.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, i.caKey.Public(), i.caKey)
+}
+
+func (i *Instance) initRekor(t *testing.T) {
+	t.Helper()
+	var err error
+	i.rekorKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(i.rekorKey.Public())
+	require.NoError(t, err)
+	sum := sha256.Sum256(der)
+	i.logID = sum[:]
+}
+
+func (i *Instance) initTSA(t *testing.T) {
+	t.Helper()
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	i.tsaRoot = createCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "sigstoretest TSA root"},
+		NotBefore:             i.start,
+		NotAfter:              i.start.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, rootKey.Public(), rootKey)
+
+	i.tsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	i.tsaCert = createCertificate(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "sigstoretest TSA"},
+		NotBefore: i.start,
+		NotAfter:  i.start.Add(24 * time.Hour),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+		// RFC 3161 requires the timestamping EKU to be critical
+		ExtraExtensions: []pkix.Extension{criticalTimestampingEKU(t)},
+	}, i.tsaRoot, i.tsaKey.Public(), rootKey)
+}
+
+func (i *Instance) initTrustedRoot(t *testing.T) {
+	t.Helper()
+	tr, err := root.NewTrustedRoot(
+		root.TrustedRootMediaType01,
+		[]root.CertificateAuthority{&root.FulcioCertificateAuthority{
+			Root:                i.caCert,
+			ValidityPeriodStart: i.start,
+			URI:                 i.FulcioURL,
+		}},
+		map[string]*root.TransparencyLog{},
+		[]root.TimestampingAuthority{&root.SigstoreTimestampingAuthority{
+			Root:                i.tsaRoot,
+			Leaf:                i.tsaCert,
+			ValidityPeriodStart: i.start,
+			URI:                 i.TimestampAuthorityURL,
+		}},
+		map[string]*root.TransparencyLog{
+			hex.EncodeToString(i.logID): {
+				BaseURL:             i.RekorURL,
+				ID:                  i.logID,
+				ValidityPeriodStart: i.start,
+				HashFunc:            crypto.SHA256,
+				PublicKey:           i.rekorKey.Public(),
+				SignatureHashFunc:   crypto.SHA256,
+			},
+		},
+	)
+	require.NoError(t, err)
+	i.TrustedRoot, err = tr.MarshalJSON()
+	require.NoError(t, err)
+}
+
+// createCertificate issues a certificate from the template. If parent is
+// nil, the certificate is self signed.
+func createCertificate(t *testing.T, template, parent *x509.Certificate, pub crypto.PublicKey, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+	serial, err := rand.Int(rand.Reader, big128)
+	require.NoError(t, err)
+	template.SerialNumber = serial
+	if parent == nil {
+		parent = template
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sigstoretest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/require"
+	"github.com/theupdateframework/go-tuf/v2/metadata"
+)
+
+// initTuf builds a TUF repository publishing the trusted root. A single
+// key signs all the roles and snapshots are not consistent to keep the
+// served paths simple.
+func (i *Instance) initTuf(t *testing.T) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := signature.LoadED25519Signer(priv)
+	require.NoError(t, err)
+	key, err := metadata.KeyFromPublicKey(pub)
+	require.NoError(t, err)
+
+	expires := time.Now().Add(24 * time.Hour)
+
+	root := metadata.Root(expires)
+	root.Signed.ConsistentSnapshot = false
+	for _, role := range []string{metadata.ROOT, metadata.TARGETS, metadata.SNAPSHOT, metadata.TIMESTAMP} {
+		require.NoError(t, root.Signed.AddKey(key, role))
+	}
+
+	targets := metadata.Targets(expires)
+	target, err := metadata.TargetFile().FromBytes("trusted_root.json", i.TrustedRoot, "sha256")
+	require.NoError(t, err)
+	targets.Signed.Targets["trusted_root.json"] = target
+
+	snapshot := metadata.Snapshot(expires)
+	snapshot.Signed.Meta["targets.json"] = metadata.MetaFile(targets.Signed.Version)
+
+	timestamp := metadata.Timestamp(expires)
+	timestamp.Signed.Meta["snapshot.json"] = metadata.MetaFile(snapshot.Signed.Version)
+
+	i.tuf = map[string][]byte{"targets/trusted_root.json": i.TrustedRoot}
+	for name, md := range map[string]interface {
+		Sign(signature.Signer) (*metadata.Signature, error)
+		ToBytes(bool) ([]byte, error)
+	}{
+		"1.root.json": root, "targets.json": targets, "snapshot.json": snapshot, "timestamp.json": timestamp,
+	} {
+		_, err := md.Sign(signer)
+		require.NoError(t, err)
+		i.tuf[name], err = md.ToBytes(false)
+		require.NoError(t, err)
+	}
+	i.TufRoot = i.tuf["1.root.json"]
+}
+
+// handleTuf serves the TUF repository files
+func (i *Instance) handleTuf(w http.ResponseWriter, r *http.Request) {
+	data, ok := i.tuf[strings.TrimPrefix(r.URL.Path, "/tuf/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data) //nolint:errcheck,gosec
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package idtoken implements helpers to read the claims of OIDC identity
+// tokens obtained from the ambient credential providers. The token signature
+// is not verified here, that is the job of the Fulcio server.
+package idtoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+)
+
+// Claims captures the identity token claims relevant for signing
+type Claims struct {
+	Issuer   string   `json:"iss"`
+	Subject  string   `json:"sub"`
+	Email    string   `json:"email"`
+	Audience Audience `json:"aud"`
+	Expiry   int64    `json:"exp"`
+	IssuedAt int64    `json:"iat"`
+}
+
+// Audience is the token audience claim. It can be encoded as a string or
+// as a list of strings.
+type Audience []string
+
+// UnmarshalJSON decodes the audience from either of its forms
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(data, &l); err != nil {
+		return fmt.Errorf("decoding audience: %w", err)
+	}
+	*a = l
+	return nil
+}
+
+// Parse decodes the claims from a raw JWT
+func Parse(raw string) (*Claims, error) {
+	parts := strings.Split(strings.TrimSpace(raw), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("identity token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+
+	claims := &Claims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("parsing token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// HasAudience returns true if the audience is listed in the token claims
+func (c *Claims) HasAudience(audience string) bool {
+	return slices.Contains(c.Audience, audience)
+}
+
+// ExpiresAt returns the token expiration time. If the token has no
+// expiration, it returns the zero time.
+func (c *Claims) ExpiresAt() time.Time {
+	if c.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.Expiry, 0)
+}
+
+// Expired returns true if the token expiration time has passed
+func (c *Claims) Expired() bool {
+	return c.Expiry != 0 && time.Now().After(c.ExpiresAt())
+}
+
+// SubjectIdentity returns the identity Fulcio will bind to the certificate:
+// the email if the token has one, the subject otherwise.
+func (c *Claims) SubjectIdentity() string {
+	if c.Email != "" {
+		return c.Email
+	}
+	return c.Subject
+}
+
+// Token parses a raw JWT and returns it as an OIDC token with its subject
+// populated.
+func Token(raw string) (*oauthflow.OIDCIDToken, error) {
+	raw = strings.TrimSpace(raw)
+	claims, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthflow.OIDCIDToken{
+		RawString: raw,
+		Subject:   claims.SubjectIdentity(),
+	}, nil
+}
+
+// ReadFile reads a token from a file, checks that it is not expired and
+// returns it parsed.
+func ReadFile(path string) (*oauthflow.OIDCIDToken, *Claims, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading token file: %w", err)
+	}
+
+	raw := strings.TrimSpace(string(data))
+	claims, err := Parse(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing token from %s: %w", path, err)
+	}
+
+	if claims.Expired() {
+		return nil, nil, fmt.Errorf("token in %s expired at %s", path, claims.ExpiresAt().Format(time.RFC3339))
+	}
+
+	return &oauthflow.OIDCIDToken{
+		RawString: raw,
+		Subject:   claims.SubjectIdentity(),
+	}, claims, nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package idtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		raw       string
+		mustErr   bool
+		audience  []string
+		expired   bool
+		principal string
+	}{
+		{"string-aud", ststest.Token(t, nil), false, []string{"sigstore"}, false, "test-subject"},
+		{"list-aud", ststest.Token(t, map[string]any{"aud": []string{"a", "sigstore"}}), false, []string{"a", "sigstore"}, false, "test-subject"},
+		{"email", ststest.Token(t, map[string]any{"email": "jdoe@example.com"}), false, []string{"sigstore"}, false, "jdoe@example.com"},
+		{"expired", ststest.Token(t, map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}), false, []string{"sigstore"}, true, "test-subject"},
+		{"not-jwt", "not.a-jwt", true, nil, false, ""},
+		{"bad-payload", "a.!!!.c", true, nil, false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			claims, err := Parse(tc.raw)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.audience, []string(claims.Audience))
+			require.True(t, claims.HasAudience("sigstore"))
+			require.Equal(t, tc.expired, claims.Expired())
+			require.Equal(t, tc.principal, claims.SubjectIdentity())
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	t.Parallel()
+	token, claims, err := ReadFile(ststest.TokenFile(t, nil))
+	require.NoError(t, err)
+	require.Equal(t, "test-subject", token.Subject)
+	require.Equal(t, "https://issuer.example.com", claims.Issuer)
+
+	_, _, err = ReadFile(ststest.TokenFile(t, map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}))
+	require.Error(t, err)
+}
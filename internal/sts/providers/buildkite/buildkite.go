@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package buildkite requests OIDC tokens from the Buildkite agent API
+package buildkite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+const (
+	VariableAgentAccessToken = "BUILDKITE_AGENT_ACCESS_TOKEN"
+	VariableAgentEndpoint    = "BUILDKITE_AGENT_ENDPOINT"
+	VariableJobID            = "BUILDKITE_JOB_ID"
+
+	defaultAgentEndpoint = "https://agent.buildkite.com/v3"
+)
+
+type Agent struct{}
+
+// Provide requests a token for the running job from the agent API
+func (a *Agent) Provide(ctx context.Context, audience string) (*oauthflow.OIDCIDToken, error) {
+	accessToken := os.Getenv(VariableAgentAccessToken)
+	jobID := os.Getenv(VariableJobID)
+	if accessToken == "" || jobID == "" {
+		return nil, nil
+	}
+
+	endpoint := os.Getenv(VariableAgentEndpoint)
+	if endpoint == "" {
+		endpoint = defaultAgentEndpoint
+	}
+
+	body, err := json.Marshal(map[string]string{"audience": audience})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		fmt.Sprintf("%s/jobs/%s/oidc/tokens", strings.TrimSuffix(endpoint, "/"), jobID),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Token "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token from buildkite agent API: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:errcheck
+		return nil, fmt.Errorf("buildkite agent API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var payload struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+
+	return idtoken.Token(payload.Token)
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package buildkite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestProvide(t *testing.T) {
+	jwt := ststest.Token(t, map[string]any{"sub": "organization:acme:pipeline:app:ref:refs/heads/main"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/jobs/job-1234/oidc/tokens" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Token agent-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Audience string `json:"audience"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Audience != "sigstore" {
+			http.Error(w, "bad audience", http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"token": jwt}) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	for _, tc := range []struct {
+		name        string
+		env         map[string]string
+		audience    string
+		mustErr     bool
+		expectToken bool
+	}{
+		{"not-buildkite", map[string]string{VariableAgentAccessToken: "", VariableJobID: ""}, "sigstore", false, false},
+		{
+			"token",
+			map[string]string{VariableAgentAccessToken: "agent-token", VariableJobID: "job-1234", VariableAgentEndpoint: server.URL + "/v3"},
+			"sigstore", false, true,
+		},
+		{
+			"bad-credentials",
+			map[string]string{VariableAgentAccessToken: "wrong", VariableJobID: "job-1234", VariableAgentEndpoint: server.URL + "/v3"},
+			"sigstore", true, false,
+		},
+		{
+			"bad-audience",
+			map[string]string{VariableAgentAccessToken: "agent-token", VariableJobID: "job-1234", VariableAgentEndpoint: server.URL + "/v3/"},
+			"other", true, false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			token, err := (&Agent{}).Provide(t.Context(), tc.audience)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if !tc.expectToken {
+				require.Nil(t, token)
+				return
+			}
+			require.Equal(t, jwt, token.RawString)
+			require.Equal(t, "organization:acme:pipeline:app:ref:refs/heads/main", token.Subject)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package circleci obtains OIDC tokens in CircleCI jobs
+package circleci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+	"github.com/sirupsen/logrus"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+const (
+	VariableCircleCI = "CIRCLECI"
+	VariableTokenV2  = "CIRCLE_OIDC_TOKEN_V2"
+	VariableToken    = "CIRCLE_OIDC_TOKEN"
+
+	defaultCommand = "circleci"
+)
+
+type CI struct {
+	// Command is the CircleCI CLI used to mint tokens with a custom
+	// audience. Defaults to circleci.
+	Command string
+}
+
+// Provide returns an OIDC token for the job. It first tries to mint a token
+// with the requested audience using the CircleCI CLI, falling back to the
+// tokens CircleCI injects in the environment.
+func (ci *CI) Provide(ctx context.Context, audience string) (*oauthflow.OIDCIDToken, error) {
+	if os.Getenv(VariableCircleCI) != "true" {
+		return nil, nil
+	}
+
+	command := ci.Command
+	if command == "" {
+		command = defaultCommand
+	}
+
+	if path, err := exec.LookPath(command); err == nil {
+		//nolint:gosec // The command runs the CircleCI CLI
+		out, err := exec.CommandContext(
+			ctx, path, "run", "oidc", "get", "--claims", fmt.Sprintf(`{"aud":%q}`, audience),
+		).Output()
+		if err == nil {
+			return idtoken.Token(string(out))
+		}
+		logrus.Warnf("minting token with the CircleCI CLI failed, using job token: %v", err)
+	}
+
+	for _, v := range []string{VariableTokenV2, VariableToken} {
+		raw := strings.TrimSpace(os.Getenv(v))
+		if raw == "" {
+			continue
+		}
+		claims, err := idtoken.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing token from $%s: %w", v, err)
+		}
+		if !claims.HasAudience(audience) {
+			logrus.Warnf("CircleCI token audience %v does not include %q", claims.Audience, audience)
+		}
+		return idtoken.Token(raw)
+	}
+	return nil, nil
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package circleci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestProvide(t *testing.T) {
+	v1 := ststest.Token(t, map[string]any{"sub": "org/o/project/p/user/u", "aud": "org-id"})
+	v2 := ststest.Token(t, map[string]any{"sub": "org/o/project/p/user/u/vcs-origin/x", "aud": "org-id"})
+	minted := ststest.Token(t, map[string]any{"sub": "minted"})
+
+	// Fake CircleCI CLI that prints a token when called with the audience
+	dir := t.TempDir()
+	cli := filepath.Join(dir, "circleci")
+	require.NoError(t, os.WriteFile(cli, []byte(`#!/bin/sh
+[ "$5" = '{"aud":"sigstore"}' ] || exit 1
+echo "`+minted+`"
+`), 0o700)) //nolint:gosec
+
+	for _, tc := range []struct {
+		name    string
+		env     map[string]string
+		command string
+		expect  string
+	}{
+		{"not-circleci", map[string]string{VariableCircleCI: "", VariableTokenV2: v2}, "", ""},
+		{"v2", map[string]string{VariableCircleCI: "true", VariableTokenV2: v2, VariableToken: v1}, filepath.Join(dir, "none"), v2},
+		{"v1", map[string]string{VariableCircleCI: "true", VariableTokenV2: "", VariableToken: v1}, filepath.Join(dir, "none"), v1},
+		{"cli", map[string]string{VariableCircleCI: "true", VariableTokenV2: v2}, cli, minted},
+		{"no-token", map[string]string{VariableCircleCI: "true", VariableTokenV2: "", VariableToken: ""}, filepath.Join(dir, "none"), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			token, err := (&CI{Command: tc.command}).Provide(t.Context(), "sigstore")
+			require.NoError(t, err)
+			if tc.expect == "" {
+				require.Nil(t, token)
+				return
+			}
+			require.Equal(t, tc.expect, token.RawString)
+		})
+	}
+}
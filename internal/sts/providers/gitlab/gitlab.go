@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gitlab reads the sigstore identity token issued to GitLab CI jobs.
+//
+// GitLab does not expose an endpoint to request tokens, instead the pipeline
+// defines an `id_tokens` entry named SIGSTORE_ID_TOKEN with the audience
+// and GitLab injects it in the job environment:
+//
+//	id_tokens:
+//	  SIGSTORE_ID_TOKEN:
+//	    aud: sigstore
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+const VariableSigstoreIDToken = "SIGSTORE_ID_TOKEN"
+
+type CI struct{}
+
+// Provide returns the token set in the job environment
+func (ci *CI) Provide(_ context.Context, _ string) (*oauthflow.OIDCIDToken, error) {
+	raw := os.Getenv(VariableSigstoreIDToken)
+	if raw == "" {
+		return nil, nil
+	}
+
+	token, err := idtoken.Token(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing token from $%s: %w", VariableSigstoreIDToken, err)
+	}
+	return token, nil
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestProvide(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		token     string
+		mustErr   bool
+		expectNil bool
+	}{
+		{"not-set", "", false, true},
+		{"token", ststest.Token(t, map[string]any{"sub": "project_path:example/repo:ref_type:branch:ref:main"}), false, false},
+		{"invalid", "garbage", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(VariableSigstoreIDToken, tc.token)
+			token, err := (&CI{}).Provide(t.Context(), "sigstore")
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectNil {
+				require.Nil(t, token)
+				return
+			}
+			require.Equal(t, tc.token, token.RawString)
+			require.Equal(t, "project_path:example/repo:ref_type:branch:ref:main", token.Subject)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kubernetes reads projected service account tokens. To use it,
+// mount a token with the sigstore audience in the pod:
+//
+//	volumes:
+//	  - name: sigstore-token
+//	    projected:
+//	      sources:
+//	        - serviceAccountToken:
+//	            path: sigstore
+//	            audience: sigstore
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+const (
+	// VariableTokenPath overrides the default path of the token file
+	VariableTokenPath = "BND_KUBERNETES_TOKEN_PATH"
+
+	DefaultTokenPath = "/var/run/secrets/tokens/sigstore"
+)
+
+type ServiceAccount struct {
+	// TokenPath is the location of the projected token file
+	TokenPath string
+}
+
+// Provide reads the projected token from its file, the token is returned
+// only if it was issued for the requested audience.
+func (sa *ServiceAccount) Provide(_ context.Context, audience string) (*oauthflow.OIDCIDToken, error) {
+	path := sa.TokenPath
+	if path == "" {
+		path = os.Getenv(VariableTokenPath)
+	}
+	if path == "" {
+		path = DefaultTokenPath
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("checking service account token: %w", err)
+	}
+
+	token, claims, err := idtoken.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.HasAudience(audience) {
+		return nil, fmt.Errorf("projected token audience %v does not include %q", claims.Audience, audience)
+	}
+	return token, nil
+}
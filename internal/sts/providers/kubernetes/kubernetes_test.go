@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestProvide(t *testing.T) {
+	sub := "system:serviceaccount:default:builder"
+	for _, tc := range []struct {
+		name      string
+		path      string
+		mustErr   bool
+		expectNil bool
+	}{
+		{"no-file", filepath.Join(t.TempDir(), "token"), false, true},
+		{"token", ststest.TokenFile(t, map[string]any{"sub": sub}), false, false},
+		{"wrong-audience", ststest.TokenFile(t, map[string]any{"sub": sub, "aud": "https://kubernetes.default.svc"}), true, false},
+		{"expired", ststest.TokenFile(t, map[string]any{"sub": sub, "exp": time.Now().Add(-time.Hour).Unix()}), true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(VariableTokenPath, tc.path)
+			token, err := (&ServiceAccount{}).Provide(t.Context(), "sigstore")
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectNil {
+				require.Nil(t, token)
+				return
+			}
+			require.Equal(t, sub, token.Subject)
+		})
+	}
+}
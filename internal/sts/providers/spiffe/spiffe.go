@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spiffe reads JWT-SVIDs written to disk by a SPIFFE workload
+// helper (for example spiffe-helper with jwt_svids configured).
+package spiffe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+// VariableJWTSVIDPath points to the file holding the JWT-SVID
+const VariableJWTSVIDPath = "BND_SPIFFE_JWT_SVID_PATH"
+
+type JWTSVID struct {
+	// Path is the location of the JWT-SVID file
+	Path string
+}
+
+// Provide reads the JWT-SVID from its file. The SVID must be issued for the
+// requested audience and its subject must be a SPIFFE ID.
+func (s *JWTSVID) Provide(_ context.Context, audience string) (*oauthflow.OIDCIDToken, error) {
+	path := s.Path
+	if path == "" {
+		path = os.Getenv(VariableJWTSVIDPath)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	token, claims, err := idtoken.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(claims.Subject, "spiffe://") {
+		return nil, fmt.Errorf("JWT-SVID subject %q is not a SPIFFE ID", claims.Subject)
+	}
+
+	if !claims.HasAudience(audience) {
+		return nil, fmt.Errorf("JWT-SVID audience %v does not include %q", claims.Audience, audience)
+	}
+	return token, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package spiffe
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestProvide(t *testing.T) {
+	spiffeID := "spiffe://example.org/ns/ci/sa/builder"
+	for _, tc := range []struct {
+		name      string
+		path      string
+		mustErr   bool
+		expectNil bool
+	}{
+		{"not-set", "", false, true},
+		{"svid", ststest.TokenFile(t, map[string]any{"sub": spiffeID}), false, false},
+		{"not-spiffe-id", ststest.TokenFile(t, map[string]any{"sub": "builder"}), true, false},
+		{"wrong-audience", ststest.TokenFile(t, map[string]any{"sub": spiffeID, "aud": "other"}), true, false},
+		{"missing-file", filepath.Join(t.TempDir(), "svid.jwt"), true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(VariableJWTSVIDPath, tc.path)
+			token, err := (&JWTSVID{}).Provide(t.Context(), "sigstore")
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectNil {
+				require.Nil(t, token)
+				return
+			}
+			require.Equal(t, spiffeID, token.Subject)
+		})
+	}
+}
@@ -5,21 +5,144 @@ package sts
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/sigstore/sigstore/pkg/oauthflow"
+	"github.com/sirupsen/logrus"
 
+	"github.com/carabiner-dev/bnd/internal/sts/providers/buildkite"
+	"github.com/carabiner-dev/bnd/internal/sts/providers/circleci"
 	"github.com/carabiner-dev/bnd/internal/sts/providers/github"
+	"github.com/carabiner-dev/bnd/internal/sts/providers/gitlab"
+	"github.com/carabiner-dev/bnd/internal/sts/providers/kubernetes"
+	"github.com/carabiner-dev/bnd/internal/sts/providers/spiffe"
 )
 
 // Ensure the provider implement
 var (
 	_ Provider = &github.Actions{}
+	_ Provider = &gitlab.CI{}
+	_ Provider = &buildkite.Agent{}
+	_ Provider = &circleci.CI{}
+	_ Provider = &kubernetes.ServiceAccount{}
+	_ Provider = &spiffe.JWTSVID{}
 )
 
-var DefaultProviders = map[string]Provider{
-	"actions": &github.Actions{},
+// DefaultProviders is the ordered list of providers tried to get ambient
+// credentials. CI systems come first as their tokens are tied to the build,
+// workload identities (kubernetes, spiffe) are tried last.
+var DefaultProviders = []NamedProvider{
+	{"actions", &github.Actions{}},
+	{"gitlab", &gitlab.CI{}},
+	{"buildkite", &buildkite.Agent{}},
+	{"circleci", &circleci.CI{}},
+	{"kubernetes", &kubernetes.ServiceAccount{}},
+	{"spiffe", &spiffe.JWTSVID{}},
 }
 
+// ProviderNone is the provider selection that disables ambient credentials
+const ProviderNone = "none"
+
 type Provider interface {
 	Provide(context.Context, string) (*oauthflow.OIDCIDToken, error)
 }
+
+// NamedProvider is a provider with the name used to select it
+type NamedProvider struct {
+	Name     string
+	Provider Provider
+}
+
+// ProviderNames returns the names of the providers in the list
+func ProviderNames(providers []NamedProvider) []string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// SelectProviders filters a providers list. The selection is a list of
+// provider names: if any names are listed, only those providers are used
+// (in the specified order). Names prefixed with a dash exclude the
+// provider from the list. The special name "none" disables all providers.
+func SelectProviders(providers []NamedProvider, selection []string) ([]NamedProvider, error) {
+	if slices.Contains(selection, ProviderNone) {
+		if len(selection) > 1 {
+			return nil, fmt.Errorf("%q cannot be combined with other providers", ProviderNone)
+		}
+		return []NamedProvider{}, nil
+	}
+
+	index := map[string]NamedProvider{}
+	for _, p := range providers {
+		index[p.Name] = p
+	}
+
+	forced := []NamedProvider{}
+	excluded := []string{}
+	errs := []error{}
+	for _, s := range selection {
+		name := strings.TrimPrefix(s, "-")
+		p, ok := index[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown credentials provider %q (available: %s)", name, strings.Join(ProviderNames(providers), ", ")))
+			continue
+		}
+		if strings.HasPrefix(s, "-") {
+			excluded = append(excluded, name)
+		} else {
+			forced = append(forced, p)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if len(forced) == 0 {
+		forced = providers
+	}
+
+	ret := []NamedProvider{}
+	for _, p := range forced {
+		if !slices.Contains(excluded, p.Name) {
+			ret = append(ret, p)
+		}
+	}
+	return ret, nil
+}
+
+// Chain tries a list of providers in order until one returns a token
+type Chain struct {
+	Providers []NamedProvider
+
+	// ContinueOnError makes the chain try the next provider when one
+	// fails instead of returning the error.
+	ContinueOnError bool
+}
+
+// Provide runs the providers in the chain. It returns the first token
+// obtained and the name of the provider that supplied it. If no provider
+// returns a token, the token is nil.
+func (c *Chain) Provide(ctx context.Context, audience string) (*oauthflow.OIDCIDToken, string, error) {
+	for _, p := range c.Providers {
+		logrus.Debugf("trying ambient credentials from %s", p.Name)
+		token, err := p.Provider.Provide(ctx, audience)
+		if err != nil {
+			if !c.ContinueOnError {
+				return nil, p.Name, fmt.Errorf("trying ambient credentials from %s: %w", p.Name, err)
+			}
+			logrus.Warnf("ambient credentials provider %s failed, trying next: %v", p.Name, err)
+			continue
+		}
+
+		if token != nil {
+			logrus.Infof("using identity token from the %s credentials provider (subject: %s)", p.Name, token.Subject)
+			return token, p.Name, nil
+		}
+	}
+	return nil, "", nil
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package sts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	token *oauthflow.OIDCIDToken
+	err   error
+}
+
+func (fp *fakeProvider) Provide(context.Context, string) (*oauthflow.OIDCIDToken, error) {
+	return fp.token, fp.err
+}
+
+func TestSelectProviders(t *testing.T) {
+	t.Parallel()
+	providers := []NamedProvider{
+		{"a", &fakeProvider{}}, {"b", &fakeProvider{}}, {"c", &fakeProvider{}},
+	}
+	for _, tc := range []struct {
+		name      string
+		selection []string
+		expect    []string
+		mustErr   bool
+	}{
+		{"all", nil, []string{"a", "b", "c"}, false},
+		{"force", []string{"c", "a"}, []string{"c", "a"}, false},
+		{"exclude", []string{"-b"}, []string{"a", "c"}, false},
+		{"force-and-exclude", []string{"a", "b", "-b"}, []string{"a"}, false},
+		{"none", []string{ProviderNone}, []string{}, false},
+		{"none-combined", []string{ProviderNone, "a"}, nil, true},
+		{"unknown", []string{"d"}, nil, true},
+		{"unknown-exclude", []string{"-d"}, nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := SelectProviders(providers, tc.selection)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, ProviderNames(res))
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+	failing := NamedProvider{"failing", &fakeProvider{err: errors.New("boom")}}
+	empty := NamedProvider{"empty", &fakeProvider{}}
+	first := NamedProvider{"first", &fakeProvider{token: &oauthflow.OIDCIDToken{RawString: "1"}}}
+	second := NamedProvider{"second", &fakeProvider{token: &oauthflow.OIDCIDToken{RawString: "2"}}}
+
+	for _, tc := range []struct {
+		name            string
+		providers       []NamedProvider
+		continueOnError bool
+		expectProvider  string
+		mustErr         bool
+	}{
+		{"ordered", []NamedProvider{empty, first, second}, false, "first", false},
+		{"ordered-reverse", []NamedProvider{second, first}, false, "second", false},
+		{"no-token", []NamedProvider{empty}, false, "", false},
+		{"fail", []NamedProvider{failing, first}, false, "failing", true},
+		{"continue", []NamedProvider{failing, first}, true, "first", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			chain := &Chain{Providers: tc.providers, ContinueOnError: tc.continueOnError}
+			token, name, err := chain.Provide(t.Context(), "sigstore")
+			require.Equal(t, tc.expectProvider, name)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectProvider == "" {
+				require.Nil(t, token)
+				return
+			}
+			require.NotNil(t, token)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ststest has helpers to test the ambient credential providers
+package ststest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Token returns an unsigned JWT with the specified claims. Unless set in
+// the claims, the token gets the sigstore audience and expires in an hour.
+func Token(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	c := map[string]any{
+		"iss": "https://issuer.example.com",
+		"sub": "test-subject",
+		"aud": "sigstore",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		c[k] = v
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte("signature"))
+}
+
+// TokenFile writes a token to a file in a temporary directory and returns
+// its path.
+func TokenFile(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(Token(t, claims)+"\n"), 0o600))
+	return path
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
-	trustroot "github.com/sigstore/protobuf-specs/gen/pb-go/trustroot/v1"
 	"github.com/sigstore/sigstore-go/pkg/root"
 	"github.com/sigstore/sigstore-go/pkg/sign"
 	"github.com/sigstore/sigstore/pkg/oauthflow"
@@ -25,34 +24,39 @@ import (
 // BundleSigner abstracts the signer implementation to make it easy to mock
 type BundleSigner interface {
 	VerifyContent(*SignerOptions, []byte) error
-	WrapStatement([]byte) *sign.DSSEData
-	GetKeyPair(*SignerOptions) (*sign.EphemeralKeypair, error)
-	GetAmbienTokens(*SignerOptions) error
-	GetOidcToken(*SignerOptions) error
-	BuildSigstoreSignerOptions(*SignerOptions) (*sign.BundleOptions, error)
-	SignBundle(content sign.Content, keypair sign.Keypair, opts *sign.BundleOptions) (*v1.Bundle, error)
+	WrapStatement(*SignerOptions, []byte) *sign.DSSEData
+	GetKeyPair(*SignerOptions) (sign.Keypair, error)
+	GetAmbienTokens(context.Context, *SignerOptions) error
+	GetOidcToken(context.Context, *SignerOptions) error
+	BuildSigstoreSignerOptions(context.Context, *SignerOptions) (*sign.BundleOptions, error)
+	SignBundle(ctx context.Context, content sign.Content, keypair sign.Keypair, opts *sign.BundleOptions) (*v1.Bundle, error)
 }
 
 // bundleSigner implements the BundleSigner interface for the signer
 type bundleSigner struct{}
 
-func (bs *bundleSigner) WrapStatement(data []byte) *sign.DSSEData {
+// WrapStatement returns the data to sign in a DSSE envelope. The payload
+// type is read from the options, defaulting to in-toto.
+func (bs *bundleSigner) WrapStatement(opts *SignerOptions, data []byte) *sign.DSSEData {
 	content := &sign.DSSEData{
 		Data:        data,
-		PayloadType: "application/vnd.in-toto+json",
+		PayloadType: opts.GetPayloadType(),
 	}
 	return content
 }
 
-// VerifyContent checka that the attestation is in good shape to sign
-func (bs *bundleSigner) VerifyContent(*SignerOptions, []byte) error {
-	// TODO: WEnsure this is righr
-	return nil
+// VerifyContent checks that the payload is valid for its payload type
+// before signing it. Validation can be turned off in the options.
+func (bs *bundleSigner) VerifyContent(opts *SignerOptions, data []byte) error {
+	if opts.SkipValidation {
+		return nil
+	}
+	return ValidatePayload(opts.GetPayloadType(), data)
 }
 
 // GetKeyPair calls the configured key generator and returns
 // a keypair which will be used to sign
-func (bs *bundleSigner) GetKeyPair(opts *SignerOptions) (*sign.EphemeralKeypair, error) {
+func (bs *bundleSigner) GetKeyPair(opts *SignerOptions) (sign.Keypair, error) {
 	keypair, err := sign.NewEphemeralKeypair(nil)
 	if err != nil {
 		return nil, fmt.Errorf("generating ephemeral keypair")
@@ -68,102 +72,41 @@ func (bs *bundleSigner) GetKeyPair(opts *SignerOptions) (*sign.EphemeralKeypair,
 	return keypair, nil
 }
 
-func tempSigningConfigBuilder(opts *SignerOptions) (*root.SigningConfig, error) {
-	// These two are not yet exposed in the SignerOptions
-	fulcioURL := "https://fulcio.sigstore.dev"
-	rekorURL := "https://rekor.sigstore.dev"
-
-	// This is:
-	oidcIssuer := DefaultSignerOptions.OidcIssuer
-	if opts.OidcIssuer != "" {
-		oidcIssuer = opts.OidcIssuer
-	}
-
-	return root.NewSigningConfig(
-		root.SigningConfigMediaType02,
-		// Fulcio URLs
-		[]root.Service{
-			{
-				URL:                 fulcioURL,
-				MajorAPIVersion:     1,
-				ValidityPeriodStart: time.Now().Add(-time.Hour),
-				ValidityPeriodEnd:   time.Now().Add(time.Hour),
-			},
-		},
-		// OIDC Issuer
-		[]root.Service{
-			{
-				URL:                 oidcIssuer,
-				MajorAPIVersion:     1,
-				ValidityPeriodStart: time.Now().Add(-time.Hour),
-				ValidityPeriodEnd:   time.Now().Add(time.Hour),
-			},
-		},
-		// Rekor API endpoint
-		[]root.Service{
-			{
-				URL:                 rekorURL,
-				MajorAPIVersion:     1,
-				ValidityPeriodStart: time.Now().Add(-time.Hour),
-				ValidityPeriodEnd:   time.Now().Add(time.Hour),
-			},
-		},
-		root.ServiceConfiguration{
-			Selector: trustroot.ServiceSelector_ANY,
-		},
-		// Timestamp services
-		[]root.Service{
-			{
-				URL:                 "https://timestamp.githubapp.com/api/v1/timestamp",
-				MajorAPIVersion:     1,
-				ValidityPeriodStart: time.Now().Add(-time.Hour),
-				ValidityPeriodEnd:   time.Now().Add(time.Hour),
-			},
-		},
-		root.ServiceConfiguration{
-			Selector: trustroot.ServiceSelector_ANY,
-		},
-	)
-}
-
 // BuildSigstoreSignerOptions builds the signer options by reading the TUF roots
 // and configuration from the local system (or defaults).
-func (bs *bundleSigner) BuildSigstoreSignerOptions(opts *SignerOptions) (*sign.BundleOptions, error) {
+func (bs *bundleSigner) BuildSigstoreSignerOptions(ctx context.Context, opts *SignerOptions) (*sign.BundleOptions, error) {
 	if opts.Token == nil {
 		return nil, fmt.Errorf("no OIDC token set")
 	}
 
 	// bundleOptions is the options set to configure the sigstore signer
 	bundleOptions := sign.BundleOptions{}
-	tufClient, err := GetTufClient(&opts.TufOptions)
-	if err != nil {
-		return nil, fmt.Errorf("creating TUF client: %w", err)
-	}
 
-	// Call the tuf client to ensure roots are on disk
-	if _, err = root.GetTrustedRoot(tufClient); err != nil {
+	// Read the trusted root (from disk if configured) to ensure
+	// roots are available.
+	if _, err := GetTrustedRootContext(ctx, &opts.TufOptions); err != nil {
 		return nil, fmt.Errorf("fetching TUF root: %w", err)
 	}
 
-	// The TUF roots are in the process to be updated, so for now we
-	// use a temporary configuration to point to the sigstore public good
-	// while it getsa updated:
-	//
-	// signingConfig, err := root.GetSigningConfig(tufClient)
-	signingConfig, err := tempSigningConfigBuilder(opts)
+	signingConfig, err := GetSigningConfigContext(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("getting signing config from TUF: %w", err)
+		return nil, fmt.Errorf("getting signing config: %w", err)
 	}
 
-	if len(signingConfig.FulcioCertificateAuthorityURLs()) == 0 {
-		return nil, fmt.Errorf("unable to read fulcio configuration from TUF client")
+	if err := ValidateSigningConfig(signingConfig, opts); err != nil {
+		return nil, fmt.Errorf("invalid signing config: %w", err)
+	}
+
+	fulcioURL, err := SelectFulcioURL(signingConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	// Configure the Fulcio client
 	fulcioOpts := &sign.FulcioOptions{
-		BaseURL: signingConfig.FulcioCertificateAuthorityURLs()[0].URL,
-		Timeout: 30 * time.Second,
-		Retries: 1,
+		BaseURL: fulcioURL,
+		Timeout: durationOrDefault(opts.FulcioTimeout, DefaultFulcioTimeout),
+		Retries: opts.FulcioRetries,
 	}
 
 	bundleOptions.CertificateProvider = sign.NewFulcio(fulcioOpts)
@@ -171,24 +114,31 @@ func (bs *bundleSigner) BuildSigstoreSignerOptions(opts *SignerOptions) (*sign.B
 		IDToken: opts.Token.RawString,
 	}
 
+	if err := appendServiceOptions(opts, signingConfig, &bundleOptions); err != nil {
+		return nil, err
+	}
+
+	return &bundleOptions, nil
+}
+
+// appendServiceOptions configures the timestamp authorities and transparency
+// logs from the signing config into the bundle options.
+func appendServiceOptions(opts *SignerOptions, signingConfig *root.SigningConfig, bundleOptions *sign.BundleOptions) error {
 	if opts.Timestamp {
-		tsaURLs, err := root.SelectServices(
-			signingConfig.TimestampAuthorityURLs(),
-			signingConfig.TimestampAuthorityURLsConfig(), []uint32{1}, time.Now(),
-		)
+		tsaURLs, err := SelectTimestampAuthorityURLs(signingConfig)
 		if err != nil {
-			return nil, fmt.Errorf("fetching time stamp authority URLs: %w", err)
+			return err
 		}
 
 		if len(tsaURLs) == 0 {
-			return nil, fmt.Errorf("no timestamp authority found in signing config")
+			return fmt.Errorf("no timestamp authority found in signing config")
 		}
 
 		for _, tsaURL := range tsaURLs {
 			tsaOpts := &sign.TimestampAuthorityOptions{
 				URL:     tsaURL,
-				Timeout: 30 * time.Second,
-				Retries: 1,
+				Timeout: durationOrDefault(opts.TimestampAuthorityTimeout, DefaultTimestampAuthorityTimeout),
+				Retries: opts.TimestampAuthorityRetries,
 			}
 			bundleOptions.TimestampAuthorities = append(
 				bundleOptions.TimestampAuthorities, sign.NewTimestampAuthority(tsaOpts),
@@ -197,22 +147,37 @@ func (bs *bundleSigner) BuildSigstoreSignerOptions(opts *SignerOptions) (*sign.B
 	}
 
 	if opts.AppendToRekor {
-		for _, rekorURL := range signingConfig.RekorLogURLs() {
+		rekorURLs, err := SelectRekorURLs(signingConfig)
+		if err != nil {
+			return err
+		}
+		for _, rekorURL := range rekorURLs {
 			rekorOpts := &sign.RekorOptions{
-				BaseURL: rekorURL.URL,
-				Timeout: 90 * time.Second,
-				Retries: 1,
+				BaseURL: rekorURL,
+				Timeout: durationOrDefault(opts.RekorTimeout, DefaultRekorTimeout),
+				Retries: opts.RekorRetries,
 			}
 			bundleOptions.TransparencyLogs = append(bundleOptions.TransparencyLogs, sign.NewRekor(rekorOpts))
 		}
 	}
 
-	return &bundleOptions, nil
+	return nil
+}
+
+// durationOrDefault returns d or the default if d is not set
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
 }
 
-// SignBundle signs the DSSE envelop and returns the new bundle
-func (bs *bundleSigner) SignBundle(content sign.Content, keypair sign.Keypair, opts *sign.BundleOptions) (*v1.Bundle, error) {
-	bndl, err := sign.Bundle(content, keypair, *opts)
+// SignBundle signs the DSSE envelop and returns the new bundle. The context
+// is passed to the sigstore clients to cancel any pending requests.
+func (bs *bundleSigner) SignBundle(ctx context.Context, content sign.Content, keypair sign.Keypair, opts *sign.BundleOptions) (*v1.Bundle, error) {
+	bundleOptions := *opts
+	bundleOptions.Context = ctx
+	bndl, err := sign.Bundle(content, keypair, bundleOptions)
 	if err != nil {
 		return nil, fmt.Errorf("signing DSSE wrapper: %w", err)
 	}
@@ -220,16 +185,28 @@ func (bs *bundleSigner) SignBundle(content sign.Content, keypair sign.Keypair, o
 	return bndl, nil
 }
 
-func (bs *bundleSigner) GetOidcToken(opts *SignerOptions) error {
+// GetOidcToken runs the OIDC flow to get an identity token. If a token is
+// already set (passed explicitly or from an ambient credentials provider),
+// it is checked to ensure it is not expired and that it has the right
+// audience before it gets sent to Fulcio.
+func (bs *bundleSigner) GetOidcToken(ctx context.Context, opts *SignerOptions) error {
+	if opts.Token != nil {
+		if err := CheckIdentityToken(opts.Token, opts.OidcClientID); err != nil {
+			return fmt.Errorf("checking identity token: %w", err)
+		}
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	//
 	// Create the OIDC connector and choose the proper flow depending on the
 	// environment.
 	//
-	// TODO(puerco): This needs to fetch the token from github actions
 	connector := &oidcConnector{}
 	switch {
-	case opts.Token != nil:
-		connector.flow = &oauthflow.StaticTokenGetter{RawToken: opts.Token.RawString}
 	case !term.IsTerminal(0):
 		connector.flow = oauthflow.NewDeviceFlowTokenGetterForIssuer(opts.OidcIssuer)
 	default:
@@ -268,24 +245,31 @@ func randomizePort(redirectURL string) string {
 	return replace
 }
 
-func (bs *bundleSigner) GetAmbienTokens(opts *SignerOptions) error {
-	// If sts providers are disabled, we're done.
-	if opts.DisableSTS {
+// GetAmbienTokens runs the ambient credentials providers chain selected in
+// the options to look for an identity token in the environment.
+func (bs *bundleSigner) GetAmbienTokens(ctx context.Context, opts *SignerOptions) error {
+	// If sts providers are disabled or we already have a token, we're done.
+	if opts.DisableSTS || opts.Token != nil {
 		return nil
 	}
 
-	ctx := context.Background()
+	providers, err := sts.SelectProviders(sts.DefaultProviders, opts.STSProviders)
+	if err != nil {
+		return fmt.Errorf("selecting credentials providers: %w", err)
+	}
 
-	for k, provider := range sts.DefaultProviders {
-		token, err := provider.Provide(ctx, opts.OidcClientID)
-		if err != nil {
-			return fmt.Errorf("trying ambien credentials from %s: %w", k, err)
-		}
+	chain := &sts.Chain{
+		Providers:       providers,
+		ContinueOnError: opts.STSContinueOnError,
+	}
 
-		if token != nil {
-			opts.Token = token
-			return nil
-		}
+	token, _, err := chain.Provide(ctx, opts.OidcClientID)
+	if err != nil {
+		return err
+	}
+
+	if token != nil {
+		opts.Token = token
 	}
 	return nil
 }
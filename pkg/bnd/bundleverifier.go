@@ -4,11 +4,16 @@
 package bnd
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
 	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
 	"github.com/sigstore/sigstore-go/pkg/root"
 	"github.com/sigstore/sigstore-go/pkg/verify"
 	"github.com/sirupsen/logrus"
@@ -18,8 +23,36 @@ import (
 // mock for testing.
 type BundleVerifier interface {
 	OpenBundle(string) (*bundle.Bundle, error)
-	BuildSigstoreVerifier(*VerificationOptions) (VerifyCapable, error)
-	RunVerification(*VerificationOptions, VerifyCapable, *bundle.Bundle) (*verify.VerificationResult, error)
+	BuildSigstoreVerifier(context.Context, *VerificationOptions) (VerifyCapable, error)
+	RunVerification(context.Context, *VerificationOptions, VerifyCapable, *bundle.Bundle) (*verify.VerificationResult, error)
+}
+
+// Errors wrapped by the verifier to classify verification failures
+var (
+	ErrSignatureVerification = errors.New("signature verification failed")
+	ErrIdentityMismatch      = errors.New("signer identity does not match")
+	ErrMissingTimestamps     = errors.New("bundle is missing required timestamps")
+	ErrSubjectMismatch       = errors.New("artifacts do not match the statement subjects")
+
+	// ErrArtifactRequired is returned when verifying a message signature
+	// without the signed artifact or its digest.
+	ErrArtifactRequired = errors.New("message signatures can only be verified against the signed artifact or its digest")
+)
+
+// hashAlgorithmNames maps the digest algorithms in bundles to their names
+var hashAlgorithmNames = map[protocommon.HashAlgorithm]string{
+	protocommon.HashAlgorithm_SHA2_256: "sha256",
+	protocommon.HashAlgorithm_SHA2_384: "sha384",
+	protocommon.HashAlgorithm_SHA2_512: "sha512",
+}
+
+// HashAlgorithmName returns the name of a bundle digest algorithm as used
+// in in-toto digest sets (eg sha256).
+func HashAlgorithmName(algo protocommon.HashAlgorithm) string {
+	if name, ok := hashAlgorithmNames[algo]; ok {
+		return name
+	}
+	return strings.ToLower(algo.String())
 }
 
 // bundleVerifier implements the BundleVerifier interface.
@@ -37,8 +70,8 @@ func (bv *bundleVerifier) OpenBundle(path string) (*bundle.Bundle, error) {
 // BuildSigstoreVerifier creates a configured sigstore verifier from the
 // configured options
 // TODO(puerco): Abstract the returned verifier
-func (bv *bundleVerifier) BuildSigstoreVerifier(opts *VerificationOptions) (VerifyCapable, error) {
-	trustedMaterial, err := bv.assembleTrustedMaterial(opts)
+func (bv *bundleVerifier) BuildSigstoreVerifier(ctx context.Context, opts *VerificationOptions) (VerifyCapable, error) {
+	trustedMaterial, err := bv.assembleTrustedMaterial(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("building trusted materials: %w", err)
 	}
@@ -54,29 +87,67 @@ func (bv *bundleVerifier) BuildSigstoreVerifier(opts *VerificationOptions) (Veri
 	return sigstoreVerifier, nil
 }
 
-func (bv *bundleVerifier) assembleTrustedMaterial(opts *VerificationOptions) (root.TrustedMaterialCollection, error) {
+func (bv *bundleVerifier) assembleTrustedMaterial(ctx context.Context, opts *VerificationOptions) (root.TrustedMaterialCollection, error) {
 	trustedMaterial := make(root.TrustedMaterialCollection, 0)
 
-	// Fetch the trusted root data
-	data, err := GetTufRoot(&opts.TufOptions)
-	if err != nil {
-		return nil, fmt.Errorf("fetching trusted root: %w", err)
+	if opts.UsesKeys() {
+		keys, err := LoadTrustedKeys(opts.KeyPaths, opts.KeyDir)
+		if err != nil {
+			return nil, fmt.Errorf("loading trusted keys: %w", err)
+		}
+		if len(keys) == 0 {
+			return nil, errors.New("no public keys found to verify")
+		}
+		trustedMaterial = append(trustedMaterial, root.NewTrustedPublicKeyMaterialFromMapping(keys))
+
+		// Key signed bundles only need the sigstore roots to verify
+		// the transparency log and timestamps.
+		if !opts.RequireTlog && !opts.RequireTimestamp {
+			return trustedMaterial, nil
+		}
 	}
 
-	trustedRoot, err := root.NewTrustedRootFromJSON(data)
+	// Fetch the trusted root data
+	tufOptions := opts.TufOptions
+	if opts.GitHubTrustRoot {
+		tufOptions = githubTufOptions(&opts.TufOptions)
+	}
+	trustedRoot, err := GetTrustedRootContext(ctx, &tufOptions)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("fetching trusted root: %w", err)
 	}
 	trustedMaterial = append(trustedMaterial, trustedRoot)
 
 	return trustedMaterial, nil
 }
 
+// githubTufOptions returns the TUF options to read GitHub's trusted root.
+// A local trusted root or a custom TUF repository in the options is
+// respected, otherwise the GitHub TUF repository is used.
+func githubTufOptions(opts *TufOptions) TufOptions {
+	ret := *opts
+	if ret.TufRootPath != "" || len(ret.TufInitialRoot) > 0 {
+		return ret
+	}
+	if ret.TufRootURL == "" || ret.TufRootURL == SigstorePublicGoodBaseURL {
+		ret.TufRootURL = GitHubTufURL
+		ret.TufInitialRoot = githubTufRoot
+	}
+	return ret
+}
+
 // buildVerifierConfig creates a verifier configuration from an options set
 func (bv *bundleVerifier) buildVerifierConfig(opts *VerificationOptions) []verify.VerifierOption {
 	config := []verify.VerifierOption{}
 
-	if opts.RequireCTlog {
+	// GitHub's instance does not log to Rekor and its certificates carry
+	// no SCTs, the signature time is proven by the TSA signed timestamp.
+	if opts.GitHubTrustRoot {
+		return append(config, verify.WithSignedTimestamps(1))
+	}
+
+	// Key signed bundles can't have SCTs as there is no certificate
+	if opts.RequireCTlog && !opts.UsesKeys() {
 		config = append(config, verify.WithSignedCertificateTimestamps(1))
 	}
 
@@ -88,51 +159,91 @@ func (bv *bundleVerifier) buildVerifierConfig(opts *VerificationOptions) []verif
 		config = append(config, verify.WithTransparencyLog(1))
 	}
 
+	// Without timestamps, key signatures are checked at the current time
+	if opts.UsesKeys() && !opts.RequireTimestamp && !opts.RequireTlog {
+		config = append(config, verify.WithCurrentTime())
+	}
+
 	return config
 }
 
 // RunVerification verifies an artifact using the provided verifier
-func (bv *bundleVerifier) RunVerification(opts *VerificationOptions, sigstoreVerifier VerifyCapable, bndl *bundle.Bundle) (*verify.VerificationResult, error) {
-	dsse := bndl.GetDsseEnvelope()
-	if dsse == nil {
-		return nil, fmt.Errorf("bundle does not wrap a DSSE envelope")
+func (bv *bundleVerifier) RunVerification(ctx context.Context, opts *VerificationOptions, sigstoreVerifier VerifyCapable, bndl *bundle.Bundle) (*verify.VerificationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	if dsse.GetPayload() == nil {
-		return nil, fmt.Errorf("unable to extract payload from DSSE envelope")
+	messageSignature := bndl.GetMessageSignature()
+	if dsse := bndl.GetDsseEnvelope(); dsse != nil {
+		if dsse.GetPayload() == nil {
+			return nil, fmt.Errorf("unable to extract payload from DSSE envelope")
+		}
+		// sigstore-go always decodes the payload as an in-toto statement
+		if dsse.GetPayloadType() != InTotoPayloadType {
+			return nil, fmt.Errorf("verifying DSSE payloads of type %q is not supported", dsse.GetPayloadType())
+		}
+	} else if messageSignature == nil {
+		return nil, fmt.Errorf("bundle has no DSSE envelope or message signature")
+	}
+
+	if err := checkTimestampMaterial(opts, bndl); err != nil {
+		return nil, err
 	}
 
 	// Build the identity policy if set in the options
 	identityPolicies := []verify.PolicyOption{}
+	identities := opts.Identities()
+	hasIdentity := len(identities) > 0
+	if hasIdentity && !opts.UsesKeys() && bndl.VerificationMaterial.GetPublicKey() != nil {
+		return nil, fmt.Errorf("%w: bundle is signed with a key, not a certificate", ErrIdentityMismatch)
+	}
+	if opts.ChecksExtensions() && !hasIdentity {
+		return nil, errors.New("certificate extensions can only be checked along with a signer identity")
+	}
 	switch {
+	case opts.UsesKeys():
+		if hasIdentity {
+			return nil, errors.New("certificate identities cannot be checked when verifying with keys")
+		}
+		logrus.Debug("Verifying with keys, signer will be matched by key fingerprint")
+		identityPolicies = append(identityPolicies, verify.WithKey())
 	case opts.SkipIdentityCheck:
 		logrus.Debug("No identity defined, signier identity will not be checked")
 		identityPolicies = append(identityPolicies, verify.WithoutIdentitiesUnsafe())
-	case opts.ExpectedIssuer != "" || opts.ExpectedIssuerRegex != "" ||
-		opts.ExpectedSan != "" || opts.ExpectedSanRegex != "":
-		expectedIdentity, err := verify.NewShortCertificateIdentity(
-			opts.ExpectedIssuer,      // Issuer
-			opts.ExpectedIssuerRegex, // issuerRegex
-			opts.ExpectedSan,         // SAN
-			opts.ExpectedSanRegex,    // SAN regex
-		)
-		if err != nil {
-			return nil, fmt.Errorf("creating expected identity: %w", err)
+	case hasIdentity:
+		// The signer must match any of the expected identities
+		for _, id := range identities {
+			expectedIdentity, err := newCertificateIdentity(id, opts.ExpectedExtensions)
+			if err != nil {
+				return nil, fmt.Errorf("creating expected identity %s: %w", id, err)
+			}
+			identityPolicies = append(identityPolicies, verify.WithCertificateIdentity(expectedIdentity))
 		}
-		identityPolicies = append(identityPolicies, verify.WithCertificateIdentity(expectedIdentity))
 	default:
 		return nil, fmt.Errorf("expected certificate issuer/identity not defined")
 	}
 
-	// Build the artifact policy if we have a digest in the options
+	// Build the artifact policy if we have an artifact or digest in the options
 	var artifactPolicy verify.ArtifactPolicyOption
-	if opts.ArtifactDigest != "" {
+	switch {
+	case opts.ArtifactPath != "":
+		f, err := os.Open(opts.ArtifactPath)
+		if err != nil {
+			return nil, fmt.Errorf("opening artifact: %w", err)
+		}
+		defer f.Close() //nolint:errcheck
+		artifactPolicy = verify.WithArtifact(f)
+	case opts.ArtifactDigest != "":
 		hexdigest, err := hex.DecodeString(opts.ArtifactDigest)
 		if err != nil {
 			return nil, fmt.Errorf("error decoding artifact digest hex string")
 		}
 		artifactPolicy = verify.WithArtifactDigest(opts.ArtifactDigestAlgo, hexdigest)
-	} else {
+	case messageSignature != nil:
+		// The digest recorded in the bundle is not enough to verify a
+		// message signature, it would not tie the signature to any file.
+		return nil, ErrArtifactRequired
+	default:
 		logrus.Debug("No artifact hash set, no subject matching will be done")
 		artifactPolicy = verify.WithoutArtifactUnsafe()
 	}
@@ -140,8 +251,51 @@ func (bv *bundleVerifier) RunVerification(opts *VerificationOptions, sigstoreVer
 		bndl, verify.NewPolicy(artifactPolicy, identityPolicies...),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("verifying: %w", err)
+		var idErr *verify.ErrNoMatchingCertificateIdentity
+		if errors.As(err, &idErr) {
+			return nil, fmt.Errorf("%w: %w", ErrIdentityMismatch, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrSignatureVerification, err)
+	}
+
+	// sigstore does not record which key verified the signature, so we
+	// note the fingerprint that matched in the results.
+	if pk := bndl.VerificationMaterial.GetPublicKey(); pk != nil && res.Signature == nil {
+		hint := []byte(pk.GetHint())
+		res.Signature = &verify.SignatureVerificationResult{PublicKeyID: &hint}
 	}
 
 	return res, nil
 }
+
+// checkTimestampMaterial checks that the bundle carries the transparency log
+// entries and timestamps required by the verification options.
+func checkTimestampMaterial(opts *VerificationOptions, bndl *bundle.Bundle) error {
+	tlogEntries := len(bndl.VerificationMaterial.GetTlogEntries())
+	signedTimestamps := len(bndl.VerificationMaterial.GetTimestampVerificationData().GetRfc3161Timestamps())
+	switch {
+	case opts.GitHubTrustRoot:
+		if signedTimestamps == 0 {
+			return fmt.Errorf("%w: no signed timestamps found", ErrMissingTimestamps)
+		}
+	case opts.RequireTlog && tlogEntries == 0:
+		return fmt.Errorf("%w: no transparency log entries found", ErrMissingTimestamps)
+	case opts.RequireTimestamp && tlogEntries+signedTimestamps == 0:
+		return fmt.Errorf("%w: no transparency log entries or signed timestamps found", ErrMissingTimestamps)
+	}
+	return nil
+}
+
+// newCertificateIdentity builds the sigstore identity matcher of an expected
+// identity, including the certificate extensions to check.
+func newCertificateIdentity(id ExpectedIdentity, extensions certificate.Extensions) (verify.CertificateIdentity, error) {
+	sanMatcher, err := verify.NewSANMatcher(id.San, id.SanRegex)
+	if err != nil {
+		return verify.CertificateIdentity{}, err
+	}
+	issuerMatcher, err := verify.NewIssuerMatcher(id.Issuer, id.IssuerRegex)
+	if err != nil {
+		return verify.CertificateIdentity{}, err
+	}
+	return verify.NewCertificateIdentity(sanMatcher, issuerMatcher, extensions)
+}
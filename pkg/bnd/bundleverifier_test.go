@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/testing/ca"
+	"github.com/sigstore/sigstore-go/pkg/tlog"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/stretchr/testify/require"
+)
+
+// githubEntity mimics a bundle signed by GitHub's instance: it has a
+// signed timestamp but no transparency log entries.
+type githubEntity struct {
+	*ca.TestEntity
+	noTimestamps bool
+}
+
+func (e *githubEntity) HasInclusionPromise() bool           { return false }
+func (e *githubEntity) HasInclusionProof() bool             { return false }
+func (e *githubEntity) TlogEntries() ([]*tlog.Entry, error) { return nil, nil }
+
+func (e *githubEntity) Timestamps() ([][]byte, error) {
+	if e.noTimestamps {
+		return nil, nil
+	}
+	return e.TestEntity.Timestamps()
+}
+
+func TestGitHubTrustRootVerification(t *testing.T) {
+	t.Parallel()
+	virtualSigstore, err := ca.NewVirtualSigstore()
+	require.NoError(t, err)
+
+	entity, err := virtualSigstore.Attest(
+		"https://github.com/example/repo/.github/workflows/release.yaml@refs/heads/main",
+		"https://token.actions.githubusercontent.com",
+		[]byte(`{"_type":"https://in-toto.io/Statement/v1","subject":[],"predicateType":"https://example.com/test","predicate":{}}`),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		opts    VerificationOptions
+		entity  verify.SignedEntity
+		mustErr bool
+	}{
+		{"github-mode", DefaultGitHubVerifierOptions, &githubEntity{TestEntity: entity}, false},
+		{"github-mode-no-timestamp", DefaultGitHubVerifierOptions, &githubEntity{TestEntity: entity, noTimestamps: true}, true},
+		{"default-options", DefaultVerifierOptions, &githubEntity{TestEntity: entity}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			bv := &bundleVerifier{}
+			v, err := verify.NewSignedEntityVerifier(virtualSigstore, bv.buildVerifierConfig(&tc.opts)...)
+			require.NoError(t, err)
+
+			_, err = v.Verify(tc.entity, verify.NewPolicy(verify.WithoutArtifactUnsafe(), verify.WithoutIdentitiesUnsafe()))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGitHubTufOptions(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		opts   TufOptions
+		expect TufOptions
+	}{
+		{
+			"defaults", TufOptions{TufRootURL: SigstorePublicGoodBaseURL},
+			TufOptions{TufRootURL: GitHubTufURL, TufInitialRoot: githubTufRoot},
+		},
+		{
+			"local-file", TufOptions{TufRootURL: SigstorePublicGoodBaseURL, TufRootPath: "trusted_root.json"},
+			TufOptions{TufRootURL: SigstorePublicGoodBaseURL, TufRootPath: "trusted_root.json"},
+		},
+		{
+			"custom-tuf", TufOptions{TufRootURL: "https://tuf.example.com", TufInitialRoot: []byte("{}")},
+			TufOptions{TufRootURL: "https://tuf.example.com", TufInitialRoot: []byte("{}")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.expect, githubTufOptions(&tc.opts))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"google.golang.org/protobuf/proto"
+)
+
+// Bundle versions that can be written out
+const (
+	BundleVersion01 = "0.1"
+	BundleVersion02 = "0.2"
+	BundleVersion03 = "0.3"
+)
+
+// BundleVersions lists the supported output bundle versions
+var BundleVersions = []string{BundleVersion01, BundleVersion02, BundleVersion03}
+
+// ConvertBundle returns a copy of a bundle reshaped to the specified bundle
+// version. Bundles up to v0.2 carry the signing certificate in an X.509
+// chain and v0.3 bundles carry only the leaf certificate. Transparency log
+// entries are required to have an inclusion promise in v0.1 bundles and an
+// inclusion proof in later versions. If the bundle cannot be represented in
+// the requested version, an error is returned.
+func ConvertBundle(bndl *v1.Bundle, version string) (*v1.Bundle, error) {
+	if bndl == nil {
+		return nil, errors.New("unable to convert nil bundle")
+	}
+	if !slices.Contains(BundleVersions, version) {
+		return nil, fmt.Errorf("unsupported bundle version %q", version)
+	}
+
+	mediaType, err := bundle.MediaTypeString(version)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, ok := proto.Clone(bndl).(*v1.Bundle)
+	if !ok {
+		return nil, errors.New("unable to copy bundle")
+	}
+	ret.MediaType = mediaType
+
+	for i, entry := range ret.GetVerificationMaterial().GetTlogEntries() {
+		switch {
+		case version == BundleVersion01 && entry.GetInclusionPromise() == nil:
+			return nil, fmt.Errorf("transparency log entry #%d has no inclusion promise (required in v%s bundles)", i, version)
+		case version != BundleVersion01 && entry.GetInclusionProof() == nil:
+			return nil, fmt.Errorf("transparency log entry #%d has no inclusion proof (required in v%s bundles)", i, version)
+		}
+	}
+
+	material := ret.GetVerificationMaterial()
+	switch content := material.GetContent().(type) {
+	case *v1.VerificationMaterial_Certificate:
+		if version != BundleVersion03 {
+			material.Content = &v1.VerificationMaterial_X509CertificateChain{
+				X509CertificateChain: &protocommon.X509CertificateChain{
+					Certificates: []*protocommon.X509Certificate{content.Certificate},
+				},
+			}
+		}
+	case *v1.VerificationMaterial_X509CertificateChain:
+		if version == BundleVersion03 {
+			certs := content.X509CertificateChain.GetCertificates()
+			if len(certs) == 0 {
+				return nil, errors.New("bundle certificate chain is empty")
+			}
+			// The chain is replaced by the leaf certificate, the rest of
+			// the chain is expected to be in the trusted root.
+			material.Content = &v1.VerificationMaterial_Certificate{Certificate: certs[0]}
+		}
+	}
+
+	if _, err := bundle.NewBundle(ret); err != nil {
+		return nil, fmt.Errorf("bundle cannot be written as v%s: %w", version, err)
+	}
+	return ret, nil
+}
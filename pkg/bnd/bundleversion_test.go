@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"os"
+	"testing"
+
+	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestConvertBundle(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("testdata/bundle-v0.1.json")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		version   string
+		mutate    func(*v1.Bundle)
+		mediaType string
+		chain     bool
+		mustErr   bool
+	}{
+		{"v0.1", BundleVersion01, nil, "application/vnd.dev.sigstore.bundle+json;version=0.1", true, false},
+		{"v0.2", BundleVersion02, nil, "application/vnd.dev.sigstore.bundle+json;version=0.2", true, false},
+		{"v0.3", BundleVersion03, nil, "application/vnd.dev.sigstore.bundle.v0.3+json", false, false},
+		{"invalid", "0.4", nil, "", false, true},
+		{
+			"v0.1-no-proof", BundleVersion01,
+			func(b *v1.Bundle) { b.GetVerificationMaterial().GetTlogEntries()[0].InclusionProof = nil },
+			"application/vnd.dev.sigstore.bundle+json;version=0.1", true, false,
+		},
+		{
+			"v0.1-no-promise", BundleVersion01,
+			func(b *v1.Bundle) { b.GetVerificationMaterial().GetTlogEntries()[0].InclusionPromise = nil }, "", false, true,
+		},
+		{
+			"v0.3-no-proof", BundleVersion03,
+			func(b *v1.Bundle) { b.GetVerificationMaterial().GetTlogEntries()[0].InclusionProof = nil }, "", false, true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			bndl := &v1.Bundle{}
+			require.NoError(t, protojson.Unmarshal(data, bndl))
+			if tc.mutate != nil {
+				tc.mutate(bndl)
+			}
+			converted, err := ConvertBundle(bndl, tc.version)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.mediaType, converted.GetMediaType())
+			require.Equal(t, tc.chain, converted.GetVerificationMaterial().GetX509CertificateChain() != nil)
+			require.Equal(t, !tc.chain, converted.GetVerificationMaterial().GetCertificate() != nil)
+
+			// Converting back must keep the leaf certificate
+			back, err := ConvertBundle(converted, BundleVersion01)
+			require.NoError(t, err)
+			require.Equal(t,
+				bndl.GetVerificationMaterial().GetX509CertificateChain().GetCertificates()[0].GetRawBytes(),
+				back.GetVerificationMaterial().GetX509CertificateChain().GetCertificates()[0].GetRawBytes(),
+			)
+		})
+	}
+}
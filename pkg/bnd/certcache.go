@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/sign"
+)
+
+// certificateExpiryMargin is the time before the certificate expiration
+// when the cached certificate is not used anymore to sign.
+const certificateExpiryMargin = 30 * time.Second
+
+// cachedCertificateProvider wraps a certificate provider to reuse the
+// certificate it issues while it is valid. This lets the signer sign many
+// statements with a single Fulcio certificate.
+type cachedCertificateProvider struct {
+	sync.Mutex
+	provider sign.CertificateProvider
+	cert     []byte
+	notAfter time.Time
+}
+
+func newCachedCertificateProvider(provider sign.CertificateProvider) *cachedCertificateProvider {
+	return &cachedCertificateProvider{provider: provider}
+}
+
+// GetCertificate returns the cached certificate or requests a new one if
+// there is none or it is about to expire.
+func (c *cachedCertificateProvider) GetCertificate(
+	ctx context.Context, keypair sign.Keypair, opts *sign.CertificateProviderOptions,
+) ([]byte, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.cert != nil && time.Now().Add(certificateExpiryMargin).Before(c.notAfter) {
+		return c.cert, nil
+	}
+
+	renewing := c.cert != nil
+	certDER, err := c.provider.GetCertificate(ctx, keypair, opts)
+	if err != nil {
+		if renewing {
+			return nil, fmt.Errorf("renewing expired signing certificate: %w", err)
+		}
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing certificate: %w", err)
+	}
+
+	c.cert = certDER
+	c.notAfter = cert.NotAfter
+	return c.cert, nil
+}
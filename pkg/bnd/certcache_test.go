@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCertificateProvider issues self signed certificates valid for a
+// fixed time and counts how many were issued.
+type fakeCertificateProvider struct {
+	validity time.Duration
+	calls    int
+	fail     bool
+}
+
+func (f *fakeCertificateProvider) GetCertificate(context.Context, sign.Keypair, *sign.CertificateProviderOptions) ([]byte, error) {
+	f.calls++
+	if f.fail {
+		return nil, errors.New("token expired")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(f.calls)),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(f.validity),
+	}
+	return x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+}
+
+func TestCachedCertificateProvider(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name        string
+		validity    time.Duration
+		expectCalls int
+	}{
+		{"valid-cert-reused", 10 * time.Minute, 1},
+		{"expiring-cert-renewed", certificateExpiryMargin / 2, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fake := &fakeCertificateProvider{validity: tc.validity}
+			cached := newCachedCertificateProvider(fake)
+			for range 3 {
+				cert, err := cached.GetCertificate(t.Context(), nil, nil)
+				require.NoError(t, err)
+				require.NotEmpty(t, cert)
+			}
+			require.Equal(t, tc.expectCalls, fake.calls)
+		})
+	}
+
+	t.Run("renew-error", func(t *testing.T) {
+		t.Parallel()
+		fake := &fakeCertificateProvider{validity: time.Second}
+		cached := newCachedCertificateProvider(fake)
+		_, err := cached.GetCertificate(t.Context(), nil, nil)
+		require.NoError(t, err)
+		fake.fail = true
+		_, err = cached.GetCertificate(t.Context(), nil, nil)
+		require.ErrorContains(t, err, "renewing expired signing certificate")
+	})
+}
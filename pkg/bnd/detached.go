@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// PreparedStatement holds the data to sign a statement out of band: the
+// DSSE envelope without signatures and its pre-authentication encoding
+// (PAE), which is the data that gets signed.
+type PreparedStatement struct {
+	Envelope *dsse.Envelope
+	PAE      []byte
+}
+
+// Digest returns the SHA256 digest of the PAE
+func (ps *PreparedStatement) Digest() []byte {
+	sum := sha256.Sum256(ps.PAE)
+	return sum[:]
+}
+
+// DetachedSignature is a signature over the PAE of a prepared statement
+// computed outside of bnd. It must come with either the signing certificate
+// or the public key to verify it.
+type DetachedSignature struct {
+	Signature []byte
+
+	// Certificate is the signing certificate, PEM or DER encoded
+	Certificate []byte
+
+	// PublicKey is the PEM encoded public key, used when the signature
+	// has no certificate.
+	PublicKey []byte
+}
+
+// PrepareStatement validates a statement and wraps it in an unsigned DSSE
+// envelope, returning it along with the PAE to be signed.
+func (s *Signer) PrepareStatement(data []byte) (*PreparedStatement, error) {
+	bundleSigner := s.getBundleSigner()
+	if err := bundleSigner.VerifyContent(&s.Options, data); err != nil {
+		return nil, fmt.Errorf("verifying content: %w", err)
+	}
+
+	content := bundleSigner.WrapStatement(&s.Options, data)
+	return &PreparedStatement{
+		Envelope: &dsse.Envelope{
+			Payload:     content.Data,
+			PayloadType: content.PayloadType,
+			Signatures:  []*dsse.Signature{},
+		},
+		PAE: content.PreAuthEncoding(),
+	}, nil
+}
+
+// AttachSignature builds a bundle from a DSSE envelope and a signature of
+// its PAE computed out of band.
+func (s *Signer) AttachSignature(envelope *dsse.Envelope, sig *DetachedSignature) (*v1.Bundle, error) {
+	return s.AttachSignatureContext(context.Background(), envelope, sig)
+}
+
+// AttachSignatureContext builds a bundle from a DSSE envelope and a
+// signature of its PAE computed out of band. The signature is checked
+// against the certificate or public key before assembling the bundle. If
+// enabled in the options, the signature is timestamped and recorded in
+// the transparency log.
+func (s *Signer) AttachSignatureContext(ctx context.Context, envelope *dsse.Envelope, sig *DetachedSignature) (*v1.Bundle, error) {
+	if err := s.Options.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case envelope == nil || len(envelope.GetPayload()) == 0:
+		return nil, errors.New("envelope has no payload")
+	case sig == nil || len(sig.Signature) == 0:
+		return nil, errors.New("no signature to attach")
+	case len(sig.Certificate) > 0 && len(sig.PublicKey) > 0:
+		return nil, errors.New("signature must come with a certificate or a public key, not both")
+	case len(sig.Certificate) == 0 && len(sig.PublicKey) == 0:
+		return nil, errors.New("a certificate or public key is required to attach a signature")
+	}
+
+	// Signatures attached out of band are handled like those done with
+	// keys, no identity token is needed.
+	bundleSigner := &keyBundleSigner{}
+	content := bundleSigner.WrapStatement(&s.Options, envelope.GetPayload())
+	if envelope.GetPayloadType() != "" {
+		content.PayloadType = envelope.GetPayloadType()
+	}
+
+	var publicKey crypto.PublicKey
+	var certDER []byte
+	if len(sig.Certificate) > 0 {
+		cert, err := parseCertificate(sig.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = cert.PublicKey
+		certDER = cert.Raw
+	} else {
+		var err error
+		publicKey, err = cryptoutils.UnmarshalPEMToPublicKey(sig.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+	}
+
+	keypair, err := newDetachedKeyPair(publicKey, sig.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := signature.LoadDefaultVerifier(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading verifier: %w", err)
+	}
+	if err := verifier.VerifySignature(
+		bytes.NewReader(sig.Signature), bytes.NewReader(content.PreAuthEncoding()),
+	); err != nil {
+		return nil, fmt.Errorf("signature does not match the statement: %w", err)
+	}
+
+	bundleOptions, err := bundleSigner.BuildSigstoreSignerOptions(ctx, &s.Options)
+	if err != nil {
+		return nil, fmt.Errorf("building options: %w", err)
+	}
+	if certDER != nil {
+		bundleOptions.CertificateProvider = &staticCertificateProvider{certDER: certDER}
+	}
+
+	bndl, err := bundleSigner.SignBundle(ctx, content, keypair, bundleOptions)
+	if err != nil {
+		return nil, fmt.Errorf("assembling bundle: %w", err)
+	}
+	return bndl, nil
+}
+
+// parseCertificate parses a PEM or DER encoded certificate
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("expected a PEM certificate, got %q", block.Type)
+		}
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// newDetachedKeyPair returns a keypair that produces a signature computed
+// out of band instead of signing.
+func newDetachedKeyPair(publicKey crypto.PublicKey, sig []byte) (*KeyPair, error) {
+	details, err := signature.GetDefaultAlgorithmDetails(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key: %w", err)
+	}
+
+	hint, err := KeyFingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{
+		signer:    &detachedSigner{publicKey: publicKey, signature: sig},
+		publicKey: publicKey,
+		details:   details,
+		hint:      []byte(hint),
+	}, nil
+}
+
+// detachedSigner implements the sigstore signer interface returning a
+// precomputed signature.
+type detachedSigner struct {
+	publicKey crypto.PublicKey
+	signature []byte
+}
+
+func (ds *detachedSigner) PublicKey(...signature.PublicKeyOption) (crypto.PublicKey, error) {
+	return ds.publicKey, nil
+}
+
+func (ds *detachedSigner) SignMessage(io.Reader, ...signature.SignOption) ([]byte, error) {
+	return ds.signature, nil
+}
+
+// staticCertificateProvider returns a fixed certificate instead of
+// requesting one from Fulcio.
+type staticCertificateProvider struct {
+	certDER []byte
+}
+
+func (scp *staticCertificateProvider) GetCertificate(context.Context, sign.Keypair, *sign.CertificateProviderOptions) ([]byte, error) {
+	return scp.certDER, nil
+}
+
+var (
+	_ signature.Signer         = &detachedSigner{}
+	_ sign.CertificateProvider = &staticCertificateProvider{}
+)
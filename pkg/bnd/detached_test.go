@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareAndAttach(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	require.NoError(t, err)
+	pubPath := filepath.Join(t.TempDir(), "key.pub")
+	require.NoError(t, os.WriteFile(pubPath, pubPEM, 0o600))
+
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"file","digest":{"sha256":"` +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		`"}}],"predicateType":"https://example.com/test","predicate":{}}`)
+
+	signer := NewSigner()
+	signer.Options.Timestamp = false
+	signer.Options.AppendToRekor = false
+
+	prepared, err := signer.PrepareStatement(statement)
+	require.NoError(t, err)
+	require.Equal(t, statement, prepared.Envelope.GetPayload())
+	require.Empty(t, prepared.Envelope.GetSignatures())
+	sum := sha256.Sum256(prepared.PAE)
+	require.Equal(t, sum[:], prepared.Digest())
+
+	// Sign the PAE as an offline signer would
+	sig, err := ecdsa.SignASN1(rand.Reader, key, prepared.Digest())
+	require.NoError(t, err)
+
+	_, err = signer.AttachSignature(prepared.Envelope, &DetachedSignature{Signature: []byte("bad"), PublicKey: pubPEM})
+	require.Error(t, err)
+
+	_, err = signer.AttachSignature(prepared.Envelope, &DetachedSignature{Signature: sig})
+	require.Error(t, err)
+
+	pb, err := signer.AttachSignature(prepared.Envelope, &DetachedSignature{Signature: sig, PublicKey: pubPEM})
+	require.NoError(t, err)
+	require.Equal(t, sig, pb.GetDsseEnvelope().GetSignatures()[0].GetSig())
+
+	// The resulting bundle must verify with the public key
+	bndl, err := bundle.NewBundle(pb)
+	require.NoError(t, err)
+	verifier := NewVerifier()
+	verifier.Options.KeyPaths = []string{pubPath}
+	verifier.Options.RequireTlog = false
+	verifier.Options.RequireTimestamp = false
+	verifier.Options.RequireCTlog = false
+	vrfr, err := verifier.bundleVerifier.BuildSigstoreVerifier(t.Context(), &verifier.Options)
+	require.NoError(t, err)
+	_, err = verifier.bundleVerifier.RunVerification(t.Context(), &verifier.Options, vrfr, bndl)
+	require.NoError(t, err)
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+// testStatement is the statement signed in the round-trip tests
+var testStatement = []byte(sigstoretest.Statement)
+
+// emailClaims returns the token claims of a verified email identity
+func emailClaims(email string) map[string]any {
+	return map[string]any{"email": email, "email_verified": true}
+}
+
+// testTufOptions returns the options to fetch the trusted root of a fake
+// instance, with a TUF cache private to the test.
+func testTufOptions(t *testing.T, instance *sigstoretest.Instance) TufOptions {
+	t.Helper()
+	return TufOptions{
+		TufRootURL:     instance.TufURL,
+		TufInitialRoot: instance.TufRoot,
+		TufCachePath:   t.TempDir(),
+	}
+}
+
+// newTestSigner returns a signer using the services of a fake instance
+// with an identity token carrying the specified claims.
+func newTestSigner(t *testing.T, instance *sigstoretest.Instance, claims map[string]any) *Signer {
+	t.Helper()
+	token, err := ParseIdentityToken(ststest.Token(t, claims))
+	require.NoError(t, err)
+	signer := NewSigner()
+	signer.Options.TufOptions = testTufOptions(t, instance)
+	signer.Options.Token = token
+	signer.Options.FulcioURL = instance.FulcioURL
+	signer.Options.RekorURL = instance.RekorURL
+	signer.Options.TimestampAuthorityURL = instance.TimestampAuthorityURL
+	return signer
+}
+
+// newTestVerifier returns a verifier trusting a fake instance. The fake
+// certificates carry no SCTs, so certificate transparency is not required.
+func newTestVerifier(t *testing.T, instance *sigstoretest.Instance) *Verifier {
+	t.Helper()
+	verifier := NewVerifier()
+	verifier.Options.TufOptions = testTufOptions(t, instance)
+	verifier.Options.RequireCTlog = false
+	return verifier
+}
+
+// signTestStatement signs the test statement with the claims identity and
+// returns the bundle JSON.
+func signTestStatement(t *testing.T, instance *sigstoretest.Instance, claims map[string]any) []byte {
+	t.Helper()
+	bndl, err := newTestSigner(t, instance, claims).SignStatementContext(t.Context(), testStatement)
+	require.NoError(t, err)
+	data, err := protojson.Marshal(bndl)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts/idtoken"
+)
+
+// ParseIdentityToken parses a raw OIDC identity token (a JWT) and returns
+// it ready to set in the signer options. The subject is populated from the
+// token claims.
+func ParseIdentityToken(raw string) (*oauthflow.OIDCIDToken, error) {
+	token, err := idtoken.Token(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing identity token: %w", err)
+	}
+	return token, nil
+}
+
+// CheckIdentityToken checks that an identity token is usable to request a
+// certificate: it must not be expired and it has to be issued for the
+// audience. The token signature is not verified, Fulcio does that.
+func CheckIdentityToken(token *oauthflow.OIDCIDToken, audience string) error {
+	claims, err := idtoken.Parse(token.RawString)
+	if err != nil {
+		return fmt.Errorf("parsing identity token: %w", err)
+	}
+
+	if claims.Expired() {
+		return fmt.Errorf(
+			"identity token for %q expired at %s",
+			claims.SubjectIdentity(), claims.ExpiresAt().Format(time.RFC3339),
+		)
+	}
+
+	if audience != "" && !claims.HasAudience(audience) {
+		return fmt.Errorf(
+			"identity token audience %v does not include %q", claims.Audience, audience,
+		)
+	}
+
+	if token.Subject == "" {
+		token.Subject = claims.SubjectIdentity()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore/pkg/oauthflow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sts/ststest"
+)
+
+func TestCheckIdentityToken(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		raw     string
+		mustErr bool
+	}{
+		{"valid", ststest.Token(t, map[string]any{"email": "jdoe@example.com"}), false},
+		{"expired", ststest.Token(t, map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}), true},
+		{"wrong-audience", ststest.Token(t, map[string]any{"aud": "other"}), true},
+		{"not-a-jwt", "token", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultSignerOptions
+			opts.Token = &oauthflow.OIDCIDToken{RawString: tc.raw}
+
+			// The check runs before any flow or network call
+			err := (&bundleSigner{}).GetOidcToken(t.Context(), &opts)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "jdoe@example.com", opts.Token.Subject)
+
+			parsed, err := ParseIdentityToken(tc.raw + "\n")
+			require.NoError(t, err)
+			require.Equal(t, tc.raw, parsed.RawString)
+			require.Equal(t, "jdoe@example.com", parsed.Subject)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/sigstore/sigstore-go/pkg/tuf"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	InstancePublicGood = "public-good"
+	InstanceStaging    = "staging"
+	InstanceGitHub     = "github"
+
+	// GitHubTufURL is the TUF repository of GitHub's sigstore instance
+	GitHubTufURL = "https://tuf-repo.github.com"
+)
+
+// githubTufRoot is the TUF root used to bootstrap GitHub's TUF repository
+//
+//go:embed roots/tuf-repo.github.com/root.json
+var githubTufRoot []byte
+
+// Instance is a profile capturing the settings of a sigstore instance: its
+// TUF repository, the OIDC settings, service endpoints to sign and the
+// verification defaults.
+type Instance struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	// TufRootURL is the base URL of the instance TUF repository
+	TufRootURL string `yaml:"tuf-root-url"`
+
+	// TufInitialRootPath points to the root.json used to bootstrap trust
+	// in the TUF repository.
+	TufInitialRootPath string `yaml:"tuf-initial-root"`
+
+	// TrustedRootPath points to a local trusted_root.json. When set, TUF
+	// is not used.
+	TrustedRootPath string `yaml:"trusted-root"`
+
+	OidcIssuer            string `yaml:"oidc-issuer"`
+	OidcClientID          string `yaml:"oidc-client-id"`
+	FulcioURL             string `yaml:"fulcio-url"`
+	RekorURL              string `yaml:"rekor-url"`
+	TimestampAuthorityURL string `yaml:"timestamp-url"`
+	SigningConfigFromTUF  bool   `yaml:"signing-config-tuf"`
+	Timestamp             bool   `yaml:"timestamp"`
+	AppendToRekor         bool   `yaml:"tlog"`
+
+	// Verification defaults
+	RequireCTlog     bool `yaml:"require-ctlog"`
+	RequireTlog      bool `yaml:"require-tlog"`
+	RequireTimestamp bool `yaml:"require-timestamp"`
+
+	// GitHubTrustRoot turns on the GitHub trust root verification mode
+	GitHubTrustRoot bool `yaml:"github-trust-root"`
+
+	// tufInitialRoot holds the embedded TUF roots of the built-in instances
+	tufInitialRoot []byte
+}
+
+// UnmarshalYAML decodes an instance, defaulting all the signing and
+// verification switches to on.
+func (i *Instance) UnmarshalYAML(unmarshal func(any) error) error {
+	type plain Instance
+	p := plain{
+		Timestamp:        true,
+		AppendToRekor:    true,
+		RequireCTlog:     true,
+		RequireTlog:      true,
+		RequireTimestamp: true,
+	}
+	if err := unmarshal(&p); err != nil {
+		return err
+	}
+	*i = Instance(p)
+	return nil
+}
+
+// BuiltinInstances returns the instance profiles built into bnd
+func BuiltinInstances() []Instance {
+	return []Instance{
+		{
+			Name:                  InstancePublicGood,
+			Description:           "Sigstore public good instance",
+			TufRootURL:            SigstorePublicGoodBaseURL,
+			OidcIssuer:            DefaultSignerOptions.OidcIssuer,
+			OidcClientID:          DefaultSignerOptions.OidcClientID,
+			FulcioURL:             DefaultFulcioURL,
+			RekorURL:              DefaultRekorURL,
+			TimestampAuthorityURL: GitHubTimeStamperURL,
+			Timestamp:             true,
+			AppendToRekor:         true,
+			RequireCTlog:          true,
+			RequireTlog:           true,
+			RequireTimestamp:      true,
+		},
+		{
+			Name:                  InstanceStaging,
+			Description:           "Sigstore staging instance",
+			TufRootURL:            "https://tuf-repo-cdn.sigstage.dev",
+			OidcIssuer:            "https://oauth2.sigstage.dev/auth",
+			OidcClientID:          "sigstore",
+			FulcioURL:             "https://fulcio.sigstage.dev",
+			RekorURL:              "https://rekor.sigstage.dev",
+			TimestampAuthorityURL: "https://timestamp.sigstage.dev/api/v1/timestamp",
+			Timestamp:             true,
+			AppendToRekor:         true,
+			RequireCTlog:          true,
+			RequireTlog:           true,
+			RequireTimestamp:      true,
+			tufInitialRoot:        tuf.StagingRoot(),
+		},
+		{
+			// GitHub's instance does not use a transparency log, signatures
+			// are timestamped by GitHub's TSA.
+			Name:                  InstanceGitHub,
+			Description:           "GitHub sigstore instance (private repositories)",
+			TufRootURL:            GitHubTufURL,
+			OidcIssuer:            "https://token.actions.githubusercontent.com",
+			OidcClientID:          "sigstore",
+			FulcioURL:             "https://fulcio.githubapp.com",
+			TimestampAuthorityURL: GitHubTimeStamperURL,
+			Timestamp:             true,
+			AppendToRekor:         false,
+			RequireCTlog:          false,
+			RequireTlog:           false,
+			RequireTimestamp:      true,
+			GitHubTrustRoot:       true,
+			tufInitialRoot:        githubTufRoot,
+		},
+	}
+}
+
+// instancesFile is the format of the instances configuration file
+type instancesFile struct {
+	Instances []Instance `yaml:"instances"`
+}
+
+// DefaultInstancesConfigPath returns the path of the user instances file
+func DefaultInstancesConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bnd", "instances.yaml")
+}
+
+// LoadInstances reads user defined instance profiles from a YAML (or JSON)
+// file. Relative paths in the profiles are resolved from the file location.
+func LoadInstances(path string) ([]Instance, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading instances file: %w", err)
+	}
+
+	f := instancesFile{}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing instances file: %w", err)
+	}
+
+	base := filepath.Dir(path)
+	errs := []error{}
+	for i := range f.Instances {
+		for _, p := range []*string{&f.Instances[i].TufInitialRootPath, &f.Instances[i].TrustedRootPath} {
+			if *p != "" && !filepath.IsAbs(*p) {
+				*p = filepath.Join(base, *p)
+			}
+		}
+		if err := f.Instances[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("instance #%d: %w", i, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return f.Instances, nil
+}
+
+// GetInstance returns the instance profile with the specified name. Profiles
+// defined in the config file take precedence over the built-in ones. If the
+// config path is empty or the file does not exist only the built-in
+// instances are looked up.
+func GetInstance(name, configPath string) (*Instance, error) {
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err == nil {
+			instances, err := LoadInstances(configPath)
+			if err != nil {
+				return nil, err
+			}
+			for i := range instances {
+				if instances[i].Name == name {
+					return &instances[i], nil
+				}
+			}
+		}
+	}
+
+	for _, i := range BuiltinInstances() {
+		if i.Name == name {
+			return &i, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown sigstore instance %q", name)
+}
+
+// Validate checks the instance settings
+func (i *Instance) Validate() error {
+	errs := []error{}
+	if i.Name == "" {
+		errs = append(errs, errors.New("instance name not set"))
+	}
+
+	if i.TufRootURL == "" && i.TrustedRootPath == "" {
+		errs = append(errs, errors.New("instance has no TUF repository or trusted root"))
+	}
+
+	for label, u := range map[string]string{
+		"TUF": i.TufRootURL, "OIDC issuer": i.OidcIssuer, "Fulcio": i.FulcioURL,
+		"Rekor": i.RekorURL, "timestamp authority": i.TimestampAuthorityURL,
+	} {
+		if u == "" {
+			continue
+		}
+		if _, err := url.ParseRequestURI(u); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s URL: %w", label, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TufInitialRoot returns the root.json to bootstrap the instance TUF
+// repository. If the instance does not define one, it returns nil and the
+// client will use the sigstore public good root.
+func (i *Instance) TufInitialRoot() ([]byte, error) {
+	if i.TufInitialRootPath != "" {
+		data, err := os.ReadFile(i.TufInitialRootPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading TUF initial root: %w", err)
+		}
+		return data, nil
+	}
+	return i.tufInitialRoot, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstance(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "instances.yaml")
+	require.NoError(t, os.WriteFile(conf, []byte(`
+instances:
+  - name: private
+    tuf-root-url: https://tuf.example.com
+    tuf-initial-root: roots/root.json
+    oidc-issuer: https://oidc.example.com
+    fulcio-url: https://fulcio.example.com
+    tlog: false
+    require-tlog: false
+  - name: staging
+    trusted-root: /etc/bnd/staging.json
+`), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "roots"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "roots", "root.json"), []byte("{}"), 0o600))
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+instances:
+  - name: broken
+    fulcio-url: not a url
+`), 0o600))
+
+	for _, tc := range []struct {
+		name     string
+		instance string
+		config   string
+		mustErr  bool
+		check    func(*testing.T, *Instance)
+	}{
+		{
+			"builtin", InstancePublicGood, "", false, func(t *testing.T, i *Instance) {
+				t.Helper()
+				require.Equal(t, SigstorePublicGoodBaseURL, i.TufRootURL)
+				require.True(t, i.RequireTlog)
+			},
+		},
+		{
+			"builtin-github", InstanceGitHub, conf, false, func(t *testing.T, i *Instance) {
+				t.Helper()
+				root, err := i.TufInitialRoot()
+				require.NoError(t, err)
+				require.NotEmpty(t, root)
+				require.False(t, i.RequireTlog)
+				require.True(t, i.RequireTimestamp)
+			},
+		},
+		{
+			"user-defined", "private", conf, false, func(t *testing.T, i *Instance) {
+				t.Helper()
+				require.Equal(t, filepath.Join(dir, "roots", "root.json"), i.TufInitialRootPath)
+				require.Equal(t, "https://fulcio.example.com", i.FulcioURL)
+				require.False(t, i.AppendToRekor)
+				require.False(t, i.RequireTlog)
+				// Unset switches default to on
+				require.True(t, i.Timestamp)
+				require.True(t, i.RequireCTlog)
+				require.Equal(t, "https://oidc.example.com", i.OidcIssuer)
+				require.Empty(t, i.OidcClientID)
+
+				root, err := i.TufInitialRoot()
+				require.NoError(t, err)
+				require.Equal(t, []byte("{}"), root)
+			},
+		},
+		{
+			"user-overrides-builtin", InstanceStaging, conf, false, func(t *testing.T, i *Instance) {
+				t.Helper()
+				require.Equal(t, "/etc/bnd/staging.json", i.TrustedRootPath)
+				require.Empty(t, i.TufRootURL)
+			},
+		},
+		{"config-missing", InstanceStaging, filepath.Join(dir, "nope.yaml"), false, nil},
+		{"unknown", "nope", conf, true, nil},
+		{"invalid-config", InstancePublicGood, invalid, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			i, err := GetInstance(tc.instance, tc.config)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.instance, i.Name)
+			if tc.check != nil {
+				tc.check(t, i)
+			}
+		})
+	}
+}
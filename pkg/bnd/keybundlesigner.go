@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"golang.org/x/term"
+)
+
+// keyBundleSigner implements the BundleSigner interface to sign using a
+// private key read from disk. The resulting bundles carry a public key hint
+// as verification material instead of a Fulcio certificate, so no identity
+// token or certificate provider is ever involved.
+type keyBundleSigner struct {
+	bundleSigner
+}
+
+var _ BundleSigner = &keyBundleSigner{}
+
+// GetKeyPair loads the configured private key. If the key is encrypted and
+// no passphrase is set in the options, the user is prompted for it when
+// running in a terminal.
+func (kbs *keyBundleSigner) GetKeyPair(opts *SignerOptions) (sign.Keypair, error) {
+	if opts.KeyPath == "" {
+		return nil, errors.New("no signing key path set")
+	}
+
+	data, err := os.ReadFile(opts.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+
+	passphrase := opts.KeyPassphrase
+	if IsEncryptedKey(data) && len(passphrase) == 0 && term.IsTerminal(0) {
+		fmt.Fprint(os.Stderr, "Enter passphrase for private key: ") //nolint:errcheck
+		passphrase, err = term.ReadPassword(0)
+		fmt.Fprintln(os.Stderr) //nolint:errcheck
+		if err != nil {
+			return nil, fmt.Errorf("reading passphrase: %w", err)
+		}
+	}
+
+	keypair, err := ParseKeyPair(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("loading signing key: %w", err)
+	}
+	return keypair, nil
+}
+
+// GetAmbienTokens is a noop when signing with a key
+func (kbs *keyBundleSigner) GetAmbienTokens(context.Context, *SignerOptions) error {
+	return nil
+}
+
+// GetOidcToken is a noop when signing with a key
+func (kbs *keyBundleSigner) GetOidcToken(context.Context, *SignerOptions) error {
+	return nil
+}
+
+// BuildSigstoreSignerOptions builds the sigstore options to sign with a key.
+// No certificate provider is configured but timestamp authorities and
+// transparency logs are still honored if enabled in the options.
+func (kbs *keyBundleSigner) BuildSigstoreSignerOptions(ctx context.Context, opts *SignerOptions) (*sign.BundleOptions, error) {
+	bundleOptions := sign.BundleOptions{}
+
+	// When not contacting any services we can return early as there is
+	// no need to reach the network.
+	if !opts.Timestamp && !opts.AppendToRekor {
+		return &bundleOptions, nil
+	}
+
+	signingConfig, err := GetSigningConfigContext(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("getting signing config: %w", err)
+	}
+
+	if err := ValidateSigningConfig(signingConfig, opts); err != nil {
+		return nil, fmt.Errorf("invalid signing config: %w", err)
+	}
+
+	if err := appendServiceOptions(opts, signingConfig, &bundleOptions); err != nil {
+		return nil, err
+	}
+
+	return &bundleOptions, nil
+}
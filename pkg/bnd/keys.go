@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	_ "crypto/sha512" // Needed for P-384 and Ed25519 digests
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/youmark/pkcs8"
+)
+
+// encryptedPKCS8PEMType is the PEM block type of encrypted PKCS#8 keys
+const encryptedPKCS8PEMType = "ENCRYPTED PRIVATE KEY"
+
+var _ sign.Keypair = &KeyPair{}
+
+// KeyPair implements the sigstore keypair interface backed by a private key
+// read from disk. Supported keys are ECDSA (P-256, P-384), Ed25519 and RSA.
+type KeyPair struct {
+	signer    signature.Signer
+	publicKey crypto.PublicKey
+	details   signature.AlgorithmDetails
+	hint      []byte
+}
+
+// LoadKeyPair reads a PEM encoded private key from path. Keys can be
+// PKCS#8, PKCS#1 (RSA) or SEC 1 (EC) encoded. Encrypted PKCS#8 and
+// sigstore/cosign keys are decrypted with passphrase.
+func LoadKeyPair(path string, passphrase []byte) (*KeyPair, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+	return ParseKeyPair(data, passphrase)
+}
+
+// ParseKeyPair parses PEM encoded private key data into a signing keypair.
+func ParseKeyPair(data, passphrase []byte) (*KeyPair, error) {
+	if IsEncryptedKey(data) && len(passphrase) == 0 {
+		return nil, errors.New("private key is encrypted but no passphrase was provided")
+	}
+
+	if block, _ := pem.Decode(data); block != nil && block.Type == encryptedPKCS8PEMType {
+		privateKey, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting private key: %w", err)
+		}
+		return NewKeyPair(privateKey)
+	}
+
+	privateKey, err := cryptoutils.UnmarshalPEMToPrivateKey(data, cryptoutils.StaticPasswordFunc(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	return NewKeyPair(privateKey)
+}
+
+// NewKeyPair builds a keypair from an already parsed private key.
+func NewKeyPair(privateKey crypto.PrivateKey) (*KeyPair, error) {
+	s, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key does not implement crypto.Signer")
+	}
+
+	details, err := signature.GetDefaultAlgorithmDetails(s.Public())
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key: %w", err)
+	}
+
+	signer, err := signature.LoadSignerFromAlgorithmDetails(privateKey, details)
+	if err != nil {
+		return nil, fmt.Errorf("loading signer: %w", err)
+	}
+
+	hint, err := KeyFingerprint(s.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{
+		signer:    signer,
+		publicKey: s.Public(),
+		details:   details,
+		hint:      []byte(hint),
+	}, nil
+}
+
+// IsEncryptedKey returns true if the PEM data holds an encrypted PKCS#8 or
+// sigstore key.
+func IsEncryptedKey(data []byte) bool {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+	return block.Type == string(cryptoutils.EncryptedSigstorePrivateKeyPEMType) ||
+		block.Type == "ENCRYPTED COSIGN PRIVATE KEY" ||
+		block.Type == encryptedPKCS8PEMType
+}
+
+// KeyFingerprint returns the fingerprint used to identify a public key in
+// bundles: the base64 encoded SHA256 digest of its PKIX DER encoding. This is
+// the same hint sigstore computes for ephemeral keys.
+func KeyFingerprint(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("marshaling public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// GetHashAlgorithm returns the hash algorithm matching the key type
+func (kp *KeyPair) GetHashAlgorithm() protocommon.HashAlgorithm {
+	// PureEd25519 signs the message without prehashing it, but bundles
+	// still record a message digest, so we use SHA-512 for it.
+	if kp.details.GetHashType() == crypto.Hash(0) {
+		return protocommon.HashAlgorithm_SHA2_512
+	}
+	return kp.details.GetProtoHashType()
+}
+
+// GetHint returns the key fingerprint to set in the bundle
+func (kp *KeyPair) GetHint() []byte {
+	return kp.hint
+}
+
+// GetKeyAlgorithm returns the key algorithm label
+func (kp *KeyPair) GetKeyAlgorithm() string {
+	switch kp.details.GetKeyType() {
+	case signature.ECDSA:
+		return "ECDSA"
+	case signature.RSA:
+		return "RSA"
+	case signature.ED25519:
+		return "ED25519"
+	default:
+		return ""
+	}
+}
+
+// GetPublicKey returns the public half of the keypair
+func (kp *KeyPair) GetPublicKey() crypto.PublicKey {
+	return kp.publicKey
+}
+
+// GetPublicKeyPem returns the public key PEM encoded
+func (kp *KeyPair) GetPublicKeyPem() (string, error) {
+	data, err := cryptoutils.MarshalPublicKeyToPEM(kp.publicKey)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SignData signs data and returns the signature and the data digest
+func (kp *KeyPair) SignData(_ context.Context, data []byte) (sig, digest []byte, err error) {
+	hf := kp.details.GetHashType()
+	if hf == crypto.Hash(0) {
+		hf = crypto.SHA512
+	}
+	hasher := hf.New()
+	hasher.Write(data)
+	digest = hasher.Sum(nil)
+
+	sig, err = kp.signer.SignMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("signing data: %w", err)
+	}
+	return sig, digest, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+)
+
+func TestParseKeyPair(t *testing.T) {
+	t.Parallel()
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	plainPKCS8 := func(k crypto.PrivateKey) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+
+	encryptedPKCS8 := func(k crypto.PrivateKey) []byte {
+		der, err := pkcs8.MarshalPrivateKey(k, []byte("s3cr3t"), nil)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+	}
+
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	encrypted, err := cryptoutils.MarshalPrivateKeyToEncryptedDER(ecKey, cryptoutils.StaticPasswordFunc([]byte("s3cr3t")))
+	require.NoError(t, err)
+	encryptedPEM := cryptoutils.PEMEncode(cryptoutils.EncryptedSigstorePrivateKeyPEMType, encrypted)
+
+	for _, tc := range []struct {
+		name       string
+		data       []byte
+		passphrase []byte
+		algo       string
+		mustErr    bool
+	}{
+		{"ecdsa-p256-pkcs8", plainPKCS8(ecKey), nil, "ECDSA", false},
+		{"ecdsa-p256-sec1", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}), nil, "ECDSA", false},
+		{"ecdsa-p384", plainPKCS8(ec384Key), nil, "ECDSA", false},
+		{"ed25519", plainPKCS8(edKey), nil, "ED25519", false},
+		{"rsa-pkcs1", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), nil, "RSA", false},
+		{"rsa-pkcs8", plainPKCS8(rsaKey), nil, "RSA", false},
+		{"encrypted-pkcs8-p256", encryptedPKCS8(ecKey), []byte("s3cr3t"), "ECDSA", false},
+		{"encrypted-pkcs8-ed25519", encryptedPKCS8(edKey), []byte("s3cr3t"), "ED25519", false},
+		{"encrypted-pkcs8-rsa", encryptedPKCS8(rsaKey), []byte("s3cr3t"), "RSA", false},
+		{"encrypted-pkcs8-no-pass", encryptedPKCS8(ecKey), nil, "", true},
+		{"encrypted-pkcs8-bad-pass", encryptedPKCS8(ecKey), []byte("nope"), "", true},
+		{"encrypted", encryptedPEM, []byte("s3cr3t"), "ECDSA", false},
+		{"encrypted-no-pass", encryptedPEM, nil, "", true},
+		{"encrypted-bad-pass", encryptedPEM, []byte("nope"), "", true},
+		{"garbage", []byte("not a key"), nil, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := ParseKeyPair(tc.data, tc.passphrase)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.algo, kp.GetKeyAlgorithm())
+
+			// The hint must match the public key fingerprint
+			fp, err := KeyFingerprint(kp.GetPublicKey())
+			require.NoError(t, err)
+			require.Equal(t, fp, string(kp.GetHint()))
+
+			// Sign some DSSE data and check the signature with the default
+			// sigstore verifier for the key.
+			content := &sign.DSSEData{Data: []byte(`{}`), PayloadType: "application/vnd.in-toto+json"}
+			sig, digest, err := kp.SignData(t.Context(), content.PreAuthEncoding())
+			require.NoError(t, err)
+			require.NotEmpty(t, digest)
+
+			verifier, err := signature.LoadDefaultVerifier(kp.GetPublicKey())
+			require.NoError(t, err)
+			require.NoError(t, verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(content.PreAuthEncoding())))
+		})
+	}
+}
@@ -5,8 +5,23 @@ package bnd
 
 import (
 	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/sigstore/sigstore/pkg/oauthflow"
+
+	"github.com/carabiner-dev/bnd/internal/sts"
+)
+
+// Default timeouts for the sigstore service clients
+const (
+	DefaultFulcioTimeout             = 30 * time.Second
+	DefaultRekorTimeout              = 90 * time.Second
+	DefaultTimestampAuthorityTimeout = 30 * time.Second
 )
 
 var DefaultSignerOptions = SignerOptions{
@@ -18,6 +33,13 @@ var DefaultSignerOptions = SignerOptions{
 	Timestamp:     true,
 	AppendToRekor: true,
 
+	FulcioTimeout:             DefaultFulcioTimeout,
+	FulcioRetries:             1,
+	RekorTimeout:              DefaultRekorTimeout,
+	RekorRetries:              1,
+	TimestampAuthorityTimeout: DefaultTimestampAuthorityTimeout,
+	TimestampAuthorityRetries: 1,
+
 	OidcRedirectURL: "http://localhost:0/auth/callback",
 	OidcIssuer:      "https://oauth2.sigstore.dev/auth",
 	OidcClientID:    "sigstore",
@@ -31,6 +53,55 @@ type SignerOptions struct {
 	AppendToRekor bool
 	DisableSTS    bool
 
+	// SkipValidation turns off the payload checks before signing
+	SkipValidation bool
+
+	// PayloadType is the DSSE payload type of the signed data. When empty,
+	// data is signed as an in-toto statement.
+	PayloadType string
+
+	// BundleVersion is the version of the bundles written by the signer
+	// (0.1, 0.2 or 0.3). When empty, bundles are written as produced.
+	BundleVersion string
+
+	// STSProviders selects the ambient credentials providers to try. Names
+	// listed force the use of only those providers, names prefixed with a
+	// dash exclude them. When empty, all providers are tried in order.
+	STSProviders []string
+
+	// STSContinueOnError makes the signer try the next credentials provider
+	// when one fails instead of aborting.
+	STSContinueOnError bool
+
+	// KeyPath is the path to a private key to sign with. When set, the
+	// signer uses the key instead of the keyless (Fulcio) flow.
+	KeyPath string
+
+	// KeyPassphrase is the passphrase to decrypt the private key
+	KeyPassphrase []byte
+
+	// Service endpoints. When set, they override the services defined in
+	// the signing config.
+	FulcioURL             string
+	RekorURL              string
+	TimestampAuthorityURL string
+
+	// SigningConfigPath points to a signing_config.json file to read the
+	// services configuration from.
+	SigningConfigPath string
+
+	// SigningConfigFromTUF reads the signing config from the TUF repository
+	SigningConfigFromTUF bool
+
+	// Timeouts and retry counts of the requests to the sigstore services.
+	// A zero timeout uses the default value.
+	FulcioTimeout             time.Duration
+	FulcioRetries             uint
+	RekorTimeout              time.Duration
+	RekorRetries              uint
+	TimestampAuthorityTimeout time.Duration
+	TimestampAuthorityRetries uint
+
 	// OidcRedirectURL defines the URL that the browser will redirect to.
 	// if the port is set to 0, bind will randomize it to a high number
 	// port before starting the OIDC flow.
@@ -40,8 +111,59 @@ type SignerOptions struct {
 	OidcClientSecret string
 }
 
+// GetPayloadType returns the DSSE payload type to sign with
+func (so *SignerOptions) GetPayloadType() string {
+	if so.PayloadType == "" {
+		return InTotoPayloadType
+	}
+	return so.PayloadType
+}
+
 func (so *SignerOptions) Validate() error {
 	errs := []error{}
+
+	if so.PayloadType != "" {
+		if _, _, err := mime.ParseMediaType(so.PayloadType); err != nil {
+			errs = append(errs, fmt.Errorf("invalid payload type: %w", err))
+		}
+	}
+
+	if so.BundleVersion != "" && !slices.Contains(BundleVersions, so.BundleVersion) {
+		errs = append(errs, fmt.Errorf("invalid bundle version %q (supported: %s)", so.BundleVersion, strings.Join(BundleVersions, ", ")))
+	}
+
+	if so.SigningConfigPath != "" && so.SigningConfigFromTUF {
+		errs = append(errs, errors.New("signing config can be read from a file or from TUF, not both"))
+	}
+
+	for label, u := range map[string]string{
+		"Fulcio": so.FulcioURL, "Rekor": so.RekorURL, "timestamp authority": so.TimestampAuthorityURL,
+	} {
+		if u == "" {
+			continue
+		}
+		if _, err := url.ParseRequestURI(u); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s URL: %w", label, err))
+		}
+	}
+
+	for label, d := range map[string]time.Duration{
+		"Fulcio": so.FulcioTimeout, "Rekor": so.RekorTimeout, "timestamp authority": so.TimestampAuthorityTimeout,
+	} {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s timeout: %s", label, d))
+		}
+	}
+
+	// When signing with a key, the OIDC settings are not used
+	if so.KeyPath != "" {
+		return errors.Join(errs...)
+	}
+
+	if _, err := sts.SelectProviders(sts.DefaultProviders, so.STSProviders); err != nil {
+		errs = append(errs, err)
+	}
+
 	if so.OidcIssuer == "" {
 		errs = append(errs, errors.New("OIDC issuer not set"))
 	}
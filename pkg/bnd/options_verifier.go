@@ -3,18 +3,133 @@
 
 package bnd
 
+import (
+	"fmt"
+
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+// ExpectedIdentity is a certificate identity accepted as signer. The
+// issuer and the SAN are matched exactly or by regular expression.
+type ExpectedIdentity struct {
+	Issuer      string `json:"issuer,omitempty" yaml:"issuer"`
+	IssuerRegex string `json:"issuerRegex,omitempty" yaml:"issuer-regex"`
+	San         string `json:"identity,omitempty" yaml:"identity"`
+	SanRegex    string `json:"identityRegex,omitempty" yaml:"identity-regex"`
+}
+
+// String returns a readable representation of the identity
+func (ei ExpectedIdentity) String() string {
+	san, issuer := ei.San, ei.Issuer
+	if ei.SanRegex != "" {
+		san = fmt.Sprintf("/%s/", ei.SanRegex)
+	}
+	if ei.IssuerRegex != "" {
+		issuer = fmt.Sprintf("/%s/", ei.IssuerRegex)
+	}
+	return fmt.Sprintf("%s (issuer %s)", san, issuer)
+}
+
+// MatchedIdentity returns the expected identity that matched the signer of
+// a verified bundle, or nil if identities were not checked.
+func MatchedIdentity(result *verify.VerificationResult) *ExpectedIdentity {
+	if result == nil || result.VerifiedIdentity == nil {
+		return nil
+	}
+	id := result.VerifiedIdentity
+	return &ExpectedIdentity{
+		Issuer:      id.Issuer.Issuer,
+		IssuerRegex: id.Issuer.Regexp.String(),
+		San:         id.SubjectAlternativeName.SubjectAlternativeName,
+		SanRegex:    id.SubjectAlternativeName.Regexp.String(),
+	}
+}
+
 type VerificationOptions struct {
 	TufOptions
-	ArtifactDigest      string
-	ArtifactDigestAlgo  string
+	ArtifactDigest     string
+	ArtifactDigestAlgo string
+
+	// ArtifactPath is the path to the signed artifact. It (or the
+	// ArtifactDigest) is required to verify message signatures.
+	ArtifactPath string
+
 	ExpectedIssuer      string
 	ExpectedIssuerRegex string
 	ExpectedSan         string
 	ExpectedSanRegex    string
-	SkipIdentityCheck   bool
-	RequireCTlog        bool
-	RequireTimestamp    bool
-	RequireTlog         bool
+
+	// ExpectedIdentities are additional signer identities to accept. The
+	// bundle verifies if the signer matches any of them or the identity
+	// defined in the Expected* fields.
+	ExpectedIdentities []ExpectedIdentity
+
+	// ExpectedExtensions are values of the Fulcio certificate extensions
+	// the signer certificate must have (eg the source repository of a GitHub
+	// workflow). Empty fields are not checked. The extensions are required
+	// for all the accepted identities.
+	ExpectedExtensions certificate.Extensions
+
+	SkipIdentityCheck bool
+	RequireCTlog      bool
+	RequireTimestamp  bool
+	RequireTlog       bool
+
+	// KeyPaths and KeyDir point to public keys to trust when verifying
+	// bundles signed with a key. When keys are set, the signer identity is
+	// checked by matching the bundle key hint to the key fingerprints.
+	KeyPaths []string
+	KeyDir   string
+
+	// GitHubTrustRoot verifies bundles against the trusted root of GitHub's
+	// sigstore instance (used for attestations of private repositories).
+	// Unless a local trusted root is set, the root is fetched from GitHub's
+	// TUF repository bootstrapped with the root bundled in bnd. In this mode
+	// transparency log and SCT checks are skipped and signed timestamps are
+	// always required.
+	GitHubTrustRoot bool
+
+	// StreamWorkers is the maximum number of bundles VerifyStream verifies
+	// in parallel. Zero uses the number of CPUs.
+	StreamWorkers int
+}
+
+// Identities returns all the signer identities accepted by the options
+func (vo *VerificationOptions) Identities() []ExpectedIdentity {
+	ret := []ExpectedIdentity{}
+	if vo.ExpectedIssuer != "" || vo.ExpectedIssuerRegex != "" || vo.ExpectedSan != "" || vo.ExpectedSanRegex != "" {
+		ret = append(ret, ExpectedIdentity{
+			Issuer:      vo.ExpectedIssuer,
+			IssuerRegex: vo.ExpectedIssuerRegex,
+			San:         vo.ExpectedSan,
+			SanRegex:    vo.ExpectedSanRegex,
+		})
+	}
+	return append(ret, vo.ExpectedIdentities...)
+}
+
+// ChecksExtensions returns true if any certificate extension is expected
+func (vo *VerificationOptions) ChecksExtensions() bool {
+	return vo.ExpectedExtensions != certificate.Extensions{}
+}
+
+// UsesKeys returns true when the options are set to verify key signed bundles
+func (vo *VerificationOptions) UsesKeys() bool {
+	return len(vo.KeyPaths) > 0 || vo.KeyDir != ""
+}
+
+// DefaultGitHubVerifierOptions are the defaults to verify bundles signed
+// by GitHub's sigstore instance.
+var DefaultGitHubVerifierOptions = VerificationOptions{
+	TufOptions: TufOptions{
+		TufRootURL:     GitHubTufURL,
+		TufInitialRoot: githubTufRoot,
+		Fetcher:        defaultfetcher(),
+	},
+	ArtifactDigestAlgo: "sha256",
+	RequireTimestamp:   true,
+	GitHubTrustRoot:    true,
 }
 
 var DefaultVerifierOptions = VerificationOptions{
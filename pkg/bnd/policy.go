@@ -0,0 +1,365 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrPolicyViolation is wrapped when a verified bundle fails the checks of
+// a verification policy.
+var ErrPolicyViolation = errors.New("bundle does not comply with the policy")
+
+// Names of the rules evaluated in a policy
+const (
+	RuleSignature               = "signature"
+	RuleIdentity                = "identity"
+	RuleTlog                    = "tlog"
+	RuleTimestamps              = "timestamps"
+	RulePredicateTypes          = "predicate-types"
+	RuleSubjectNames            = "subject-names"
+	RuleSubjectDigestAlgorithms = "subject-digest-algorithms"
+	RuleMaxAge                  = "max-age"
+)
+
+// currentTimeTimestamp is the type of the timestamp sigstore records when
+// verifying at the current time. It does not prove the signature time.
+const currentTimeTimestamp = "CurrentTime"
+
+// Policy is a declarative set of verification settings and checks on the
+// signed statement. Settings not defined in the policy are left as they are
+// in the verification options.
+type Policy struct {
+	// Signer identity
+	Issuer        string `yaml:"issuer"`
+	IssuerRegex   string `yaml:"issuer-regex"`
+	Identity      string `yaml:"identity"`
+	IdentityRegex string `yaml:"identity-regex"`
+
+	// Identities lists additional accepted signer identities
+	Identities []ExpectedIdentity `yaml:"identities"`
+
+	// Keys trusted to sign the bundle
+	Keys   []string `yaml:"keys"`
+	KeyDir string   `yaml:"key-dir"`
+
+	// Verification switches
+	RequireCTlog     *bool `yaml:"require-ctlog"`
+	RequireTlog      *bool `yaml:"require-tlog"`
+	RequireTimestamp *bool `yaml:"require-timestamp"`
+
+	// PredicateTypes lists the predicate types allowed in the statement
+	PredicateTypes []string `yaml:"predicate-types"`
+
+	// SubjectNames are names that must be among the statement subjects
+	SubjectNames []string `yaml:"subject-names"`
+
+	// SubjectDigestAlgorithms are digest algorithms all subjects must have
+	SubjectDigestAlgorithms []string `yaml:"subject-digest-algorithms"`
+
+	// MaxAge is the maximum time since the bundle was signed, as proven by
+	// its verified timestamps.
+	MaxAge time.Duration `yaml:"max-age"`
+}
+
+// PolicyRuleResult is the result of evaluating a policy rule
+type PolicyRuleResult struct {
+	Rule    string `json:"rule"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// PolicyResult captures the results of evaluating a bundle against a
+// policy. Verification is only set when the bundle signature verified.
+type PolicyResult struct {
+	Verification *verify.VerificationResult
+	Rules        []PolicyRuleResult
+}
+
+// Passed returns true if all the evaluated rules passed
+func (pr *PolicyResult) Passed() bool {
+	for _, r := range pr.Rules {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadPolicy reads a verification policy from a YAML (or JSON) file.
+// Unknown keys are rejected so that typos don't disable checks. Relative
+// key paths are resolved from the file location.
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	p := &Policy{}
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+
+	base := filepath.Dir(path)
+	for i := range p.Keys {
+		if !filepath.IsAbs(p.Keys[i]) {
+			p.Keys[i] = filepath.Join(base, p.Keys[i])
+		}
+	}
+	if p.KeyDir != "" && !filepath.IsAbs(p.KeyDir) {
+		p.KeyDir = filepath.Join(base, p.KeyDir)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	return p, nil
+}
+
+// Validate checks the policy settings
+func (p *Policy) Validate() error {
+	errs := []error{}
+	if p.Issuer != "" && p.IssuerRegex != "" {
+		errs = append(errs, errors.New("only one of issuer or issuer-regex can be set"))
+	}
+	if p.Identity != "" && p.IdentityRegex != "" {
+		errs = append(errs, errors.New("only one of identity or identity-regex can be set"))
+	}
+	hasIssuer := p.Issuer != "" || p.IssuerRegex != ""
+	hasSan := p.Identity != "" || p.IdentityRegex != ""
+	if hasIssuer != hasSan {
+		errs = append(errs, errors.New("issuer and identity must be set together"))
+	}
+	for i, id := range p.Identities {
+		if (id.Issuer == "" && id.IssuerRegex == "") || (id.San == "" && id.SanRegex == "") {
+			errs = append(errs, fmt.Errorf("identities[%d]: issuer and identity must be set together", i))
+		}
+	}
+	regexes := []string{p.IssuerRegex, p.IdentityRegex}
+	for _, id := range p.Identities {
+		regexes = append(regexes, id.IssuerRegex, id.SanRegex)
+	}
+	for _, re := range regexes {
+		if _, err := regexp.Compile(re); err != nil {
+			errs = append(errs, fmt.Errorf("invalid regex: %w", err))
+		}
+	}
+	if (len(p.Keys) > 0 || p.KeyDir != "") && p.hasIdentity() {
+		errs = append(errs, errors.New("identities cannot be checked when verifying with keys"))
+	}
+	if p.MaxAge < 0 {
+		errs = append(errs, errors.New("max-age cannot be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Policy) hasIdentity() bool {
+	return p.Issuer != "" || p.IssuerRegex != "" || p.Identity != "" || p.IdentityRegex != "" ||
+		len(p.Identities) > 0
+}
+
+// ApplyVerificationOptions sets the policy verification settings in an
+// options set.
+func (p *Policy) ApplyVerificationOptions(opts *VerificationOptions) {
+	// Identities in the policy replace all the identities in the options
+	if p.hasIdentity() {
+		opts.ExpectedIssuer = p.Issuer
+		opts.ExpectedIssuerRegex = p.IssuerRegex
+		opts.ExpectedSan = p.Identity
+		opts.ExpectedSanRegex = p.IdentityRegex
+		opts.ExpectedIdentities = p.Identities
+	}
+	if len(p.Keys) > 0 || p.KeyDir != "" {
+		opts.KeyPaths = p.Keys
+		opts.KeyDir = p.KeyDir
+	}
+	for _, sw := range []struct {
+		value  *bool
+		target *bool
+	}{
+		{p.RequireCTlog, &opts.RequireCTlog},
+		{p.RequireTlog, &opts.RequireTlog},
+		{p.RequireTimestamp, &opts.RequireTimestamp},
+	} {
+		if sw.value != nil {
+			*sw.target = *sw.value
+		}
+	}
+}
+
+// EvaluatePolicy verifies a bundle against a policy
+func (v *Verifier) EvaluatePolicy(bundlePath string, policy *Policy) (*PolicyResult, error) {
+	return v.EvaluatePolicyContext(context.Background(), bundlePath, policy)
+}
+
+// EvaluatePolicyContext verifies a bundle against a policy. The policy
+// settings are applied on top of the verifier options. If the bundle fails
+// to verify or any of the rules fails, the returned error wraps the cause
+// and the result records the failed rules.
+func (v *Verifier) EvaluatePolicyContext(ctx context.Context, bundlePath string, policy *Policy) (*PolicyResult, error) {
+	bndl, err := v.bundleVerifier.OpenBundle(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening bundle: %w", err)
+	}
+	return v.evaluatePolicy(ctx, bndl, policy)
+}
+
+func (v *Verifier) evaluatePolicy(ctx context.Context, bndl *bundle.Bundle, policy *Policy) (*PolicyResult, error) {
+	opts := v.Options
+	policy.ApplyVerificationOptions(&opts)
+
+	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(ctx, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("creating verifier: %w", err)
+	}
+
+	ret := &PolicyResult{Rules: []PolicyRuleResult{}}
+	result, err := v.bundleVerifier.RunVerification(ctx, &opts, vrfr, bndl)
+	if err != nil {
+		rule := RuleSignature
+		switch ErrorKind(err) {
+		case ErrorKindIdentity:
+			rule = RuleIdentity
+		case ErrorKindTimestamps:
+			rule = RuleTimestamps
+		}
+		ret.Rules = append(ret.Rules, PolicyRuleResult{Rule: rule, Message: err.Error()})
+		return ret, fmt.Errorf("verifying bundle: %w", err)
+	}
+	ret.Verification = result
+
+	// The bundle verified, so all the verification rules passed
+	ret.Rules = append(ret.Rules, PolicyRuleResult{Rule: RuleSignature, Passed: true})
+	if opts.UsesKeys() || len(opts.Identities()) > 0 {
+		ret.Rules = append(ret.Rules, PolicyRuleResult{Rule: RuleIdentity, Passed: true})
+	}
+	if opts.RequireTlog {
+		ret.Rules = append(ret.Rules, PolicyRuleResult{Rule: RuleTlog, Passed: true})
+	}
+	if opts.RequireTimestamp {
+		ret.Rules = append(ret.Rules, PolicyRuleResult{Rule: RuleTimestamps, Passed: true})
+	}
+
+	ret.Rules = append(ret.Rules, policy.evaluateStatement(result)...)
+
+	failed := []string{}
+	for _, r := range ret.Rules {
+		if !r.Passed {
+			failed = append(failed, r.Rule)
+		}
+	}
+	if len(failed) > 0 {
+		return ret, fmt.Errorf("%w: failed rules %s", ErrPolicyViolation, strings.Join(failed, ", "))
+	}
+	return ret, nil
+}
+
+// evaluateStatement checks the verified statement against the policy rules
+func (p *Policy) evaluateStatement(result *verify.VerificationResult) []PolicyRuleResult {
+	ret := []PolicyRuleResult{}
+	st := result.Statement
+
+	if len(p.PredicateTypes) > 0 {
+		r := PolicyRuleResult{Rule: RulePredicateTypes}
+		switch {
+		case st == nil:
+			r.Message = "bundle has no in-toto statement"
+		case !slices.Contains(p.PredicateTypes, st.GetPredicateType()):
+			r.Message = fmt.Sprintf("predicate type %q is not allowed", st.GetPredicateType())
+		default:
+			r.Passed = true
+		}
+		ret = append(ret, r)
+	}
+
+	if len(p.SubjectNames) > 0 {
+		r := PolicyRuleResult{Rule: RuleSubjectNames}
+		if st == nil {
+			r.Message = "bundle has no in-toto statement"
+		} else {
+			names := []string{}
+			for _, s := range st.GetSubject() {
+				names = append(names, s.GetName())
+			}
+			missing := []string{}
+			for _, name := range p.SubjectNames {
+				if !slices.Contains(names, name) {
+					missing = append(missing, name)
+				}
+			}
+			r.Passed = len(missing) == 0
+			if !r.Passed {
+				r.Message = fmt.Sprintf("missing subjects: %s", strings.Join(missing, ", "))
+			}
+		}
+		ret = append(ret, r)
+	}
+
+	if len(p.SubjectDigestAlgorithms) > 0 {
+		r := PolicyRuleResult{Rule: RuleSubjectDigestAlgorithms}
+		if st == nil {
+			r.Message = "bundle has no in-toto statement"
+		} else {
+			r.Passed = true
+			for i, s := range st.GetSubject() {
+				for _, algo := range p.SubjectDigestAlgorithms {
+					if _, ok := s.GetDigest()[algo]; !ok {
+						r.Passed = false
+						r.Message = fmt.Sprintf("subject #%d has no %s digest", i, algo)
+						break
+					}
+				}
+				if !r.Passed {
+					break
+				}
+			}
+		}
+		ret = append(ret, r)
+	}
+
+	if p.MaxAge > 0 {
+		r := PolicyRuleResult{Rule: RuleMaxAge}
+		signed, ok := signatureTime(result)
+		switch {
+		case !ok:
+			r.Message = "bundle has no verified timestamps to determine its age"
+		case time.Since(signed) > p.MaxAge:
+			r.Message = fmt.Sprintf("bundle was signed at %s, older than %s", signed.UTC().Format(time.RFC3339), p.MaxAge)
+		default:
+			r.Passed = true
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// signatureTime returns the earliest verified timestamp of the signature
+func signatureTime(result *verify.VerificationResult) (time.Time, bool) {
+	var ret time.Time
+	for _, ts := range result.VerifiedTimestamps {
+		if ts.Type == currentTimeTimestamp {
+			continue
+		}
+		if ret.IsZero() || ts.Timestamp.Before(ret) {
+			ret = ts.Timestamp
+		}
+	}
+	return ret, !ret.IsZero()
+}
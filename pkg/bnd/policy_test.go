@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name    string
+		data    string
+		mustErr bool
+		check   func(*testing.T, *Policy)
+	}{
+		{
+			"yaml", `
+issuer: https://token.actions.githubusercontent.com
+identity-regex: ^https://github.com/example/
+require-tlog: true
+predicate-types: [https://slsa.dev/provenance/v1]
+max-age: 720h
+`, false, func(t *testing.T, p *Policy) {
+				t.Helper()
+				require.Equal(t, "https://token.actions.githubusercontent.com", p.Issuer)
+				require.NotNil(t, p.RequireTlog)
+				require.True(t, *p.RequireTlog)
+				require.Nil(t, p.RequireCTlog)
+				require.Equal(t, 720*time.Hour, p.MaxAge)
+			},
+		},
+		{
+			"json-keys", `{"keys": ["keys/signing.pub"], "subject-names": ["bnd"]}`, false,
+			func(t *testing.T, p *Policy) {
+				t.Helper()
+				require.Equal(t, []string{filepath.Join(dir, "keys", "signing.pub")}, p.Keys)
+				require.Equal(t, []string{"bnd"}, p.SubjectNames)
+			},
+		},
+		{
+			"identities", "identities:\n  - issuer: https://a\n    identity: a@example.com\n  - issuer-regex: ^https://\n    identity-regex: '@example\\.org$'\n", false,
+			func(t *testing.T, p *Policy) {
+				t.Helper()
+				require.Equal(t, []ExpectedIdentity{
+					{Issuer: "https://a", San: "a@example.com"},
+					{IssuerRegex: "^https://", SanRegex: "@example\\.org$"},
+				}, p.Identities)
+			},
+		},
+		{"empty", "", false, func(t *testing.T, p *Policy) { t.Helper(); require.Equal(t, &Policy{}, p) }},
+		{"keys-and-identity", "keys: [a.pub]\nidentity: me@example.com\nissuer: https://a\n", true, nil},
+		{"unknown-key", "predicate-type: [https://slsa.dev/provenance/v1]\n", true, nil},
+		{"unknown-key-json", `{"max_age": "1h"}`, true, nil},
+		{"issuer-without-identity", "issuer: https://a\n", true, nil},
+		{"identity-without-issuer", "identity-regex: ^me@\n", true, nil},
+		{"incomplete-identities", "identities:\n  - identity: a@example.com\n", true, nil},
+		{"bad-regex", "identity-regex: '['\n", true, nil},
+		{"bad-yaml", "issuer: [\n", true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(dir, tc.name+".yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.data), 0o600))
+			p, err := LoadPolicy(path)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, p)
+		})
+	}
+}
+
+func TestEvaluateStatement(t *testing.T) {
+	t.Parallel()
+	result := &verify.VerificationResult{
+		Statement: &intoto.Statement{
+			PredicateType: "https://slsa.dev/provenance/v1",
+			Subject: []*intoto.ResourceDescriptor{
+				{Name: "bnd", Digest: map[string]string{"sha256": "abc", "sha512": "def"}},
+				{Name: "bnd.sbom", Digest: map[string]string{"sha256": "123"}},
+			},
+		},
+		VerifiedTimestamps: []verify.TimestampVerificationResult{
+			{Type: "Tlog", Timestamp: time.Now().Add(-2 * time.Hour)},
+		},
+	}
+	for _, tc := range []struct {
+		name   string
+		policy Policy
+		passed []bool
+	}{
+		{"empty", Policy{}, []bool{}},
+		{"predicate-type", Policy{PredicateTypes: []string{"https://slsa.dev/provenance/v1"}}, []bool{true}},
+		{"predicate-type-fail", Policy{PredicateTypes: []string{"https://spdx.dev/Document"}}, []bool{false}},
+		{"subject-names", Policy{SubjectNames: []string{"bnd", "bnd.sbom"}}, []bool{true}},
+		{"subject-names-fail", Policy{SubjectNames: []string{"bnd", "other"}}, []bool{false}},
+		{"digest-algorithms", Policy{SubjectDigestAlgorithms: []string{"sha256"}}, []bool{true}},
+		{"digest-algorithms-fail", Policy{SubjectDigestAlgorithms: []string{"sha512"}}, []bool{false}},
+		{"max-age", Policy{MaxAge: 3 * time.Hour}, []bool{true}},
+		{"max-age-fail", Policy{MaxAge: time.Hour}, []bool{false}},
+		{
+			"multiple",
+			Policy{PredicateTypes: []string{"https://slsa.dev/provenance/v1"}, SubjectNames: []string{"x"}, MaxAge: time.Hour},
+			[]bool{true, false, false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rules := tc.policy.evaluateStatement(result)
+			require.Len(t, rules, len(tc.passed))
+			for i, r := range rules {
+				require.Equal(t, tc.passed[i], r.Passed, r.Rule)
+				if !r.Passed {
+					require.NotEmpty(t, r.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	t.Parallel()
+	instance := sigstoretest.New(t)
+	data := signTestStatement(t, instance, emailClaims("signer@example.com"))
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	requireCTlog := false
+	for _, tc := range []struct {
+		name   string
+		policy Policy
+		kind   string
+		rules  int
+	}{
+		{
+			"pass",
+			Policy{
+				Issuer: "https://issuer.example.com", IdentityRegex: "@example\\.com$", RequireCTlog: &requireCTlog,
+				PredicateTypes: []string{"https://example.com/test"}, MaxAge: time.Hour,
+			},
+			"", 6,
+		},
+		{
+			"identity",
+			Policy{Issuer: "https://issuer.example.com", Identity: "other@example.com", RequireCTlog: &requireCTlog},
+			ErrorKindIdentity, 1,
+		},
+		{
+			"predicate-type",
+			Policy{
+				Issuer: "https://issuer.example.com", Identity: "signer@example.com", RequireCTlog: &requireCTlog,
+				PredicateTypes: []string{"https://spdx.dev/Document"},
+			},
+			ErrorKindPolicy, 5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := newTestVerifier(t, instance)
+			res, err := verifier.EvaluatePolicyContext(t.Context(), path, &tc.policy)
+			require.Equal(t, tc.kind, ErrorKind(err))
+			require.Len(t, res.Rules, tc.rules)
+			require.Equal(t, tc.kind == "", res.Passed())
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+// ReportSchemaVersion is the version of the verification report schema.
+// Fields are only added to the report within the same schema version.
+const ReportSchemaVersion = "v1"
+
+// Kinds of verification errors in reports
+const (
+	ErrorKindSignature  = "signature"
+	ErrorKindIdentity   = "identity"
+	ErrorKindTimestamps = "timestamps"
+	ErrorKindSubject    = "subject"
+	ErrorKindPolicy     = "policy"
+	ErrorKindIO         = "io"
+	ErrorKindOther      = "other"
+)
+
+// VerificationReport is a serializable summary of a bundle verification
+type VerificationReport struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	Bundle        string            `json:"bundle,omitempty"`
+	Line          int               `json:"line,omitempty"`
+	Verified      bool              `json:"verified"`
+	Error         *ReportError      `json:"error,omitempty"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Signer        *ReportSigner     `json:"signer,omitempty"`
+	Timestamps    []ReportTimestamp `json:"timestamps,omitempty"`
+	TlogEntries   []ReportTlogEntry `json:"tlogEntries,omitempty"`
+	Statement     *ReportStatement  `json:"statement,omitempty"`
+
+	// SubjectMatch is set when local artifacts are matched to the subjects
+	SubjectMatch *ReportSubjectMatch `json:"subjectMatch,omitempty"`
+
+	// Policy has the rule results when verifying against a policy
+	Policy []PolicyRuleResult `json:"policy,omitempty"`
+}
+
+// StreamReport summarizes the verification of the bundles in a jsonl
+// stream. Each bundle is described by its own report.
+type StreamReport struct {
+	SchemaVersion string                `json:"schemaVersion"`
+	Bundle        string                `json:"bundle,omitempty"`
+	Verified      bool                  `json:"verified"`
+	Total         int                   `json:"total"`
+	Failed        int                   `json:"failed"`
+	Results       []*VerificationReport `json:"results"`
+}
+
+// ReportError describes why a verification failed
+type ReportError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// ReportSigner captures the verified signer. Keyless signers are described
+// by their certificate identity and extensions, key signers by the key ID.
+type ReportSigner struct {
+	Identity          string                  `json:"identity,omitempty"`
+	Issuer            string                  `json:"issuer,omitempty"`
+	CertificateIssuer string                  `json:"certificateIssuer,omitempty"`
+	KeyID             string                  `json:"keyId,omitempty"`
+	Extensions        *certificate.Extensions `json:"extensions,omitempty"`
+
+	// MatchedIdentity is the expected identity that matched the signer
+	MatchedIdentity *ExpectedIdentity `json:"matchedIdentity,omitempty"`
+}
+
+// ReportTimestamp is a verified timestamp of the signature
+type ReportTimestamp struct {
+	Type      string    `json:"type"`
+	URI       string    `json:"uri,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ReportTlogEntry is a transparency log entry recorded in the bundle
+type ReportTlogEntry struct {
+	LogIndex       int64     `json:"logIndex"`
+	LogID          string    `json:"logId"`
+	IntegratedTime time.Time `json:"integratedTime"`
+	Kind           string    `json:"kind,omitempty"`
+	Version        string    `json:"version,omitempty"`
+}
+
+// ReportStatement summarizes the verified in-toto statement
+type ReportStatement struct {
+	PredicateType string          `json:"predicateType"`
+	Subjects      []ReportSubject `json:"subjects"`
+}
+
+// ReportSubject is a subject of the verified statement
+type ReportSubject struct {
+	Name   string            `json:"name,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// ReportSubjectMatch records the matches of local artifacts to the
+// statement subjects.
+type ReportSubjectMatch struct {
+	Matched            bool                 `json:"matched"`
+	Subjects           []ReportMatchSubject `json:"subjects"`
+	UnmatchedArtifacts []string             `json:"unmatchedArtifacts"`
+}
+
+// ReportMatchSubject is a statement subject and the artifact matching it
+type ReportMatchSubject struct {
+	ReportSubject
+	Matched  bool   `json:"matched"`
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// NewVerificationReport builds a report from a verification result and the
+// error returned when verifying. The result details are recorded when set,
+// even if there is an error. The bundle is used to record its transparency
+// log entries, it can be nil.
+func NewVerificationReport(bndl *bundle.Bundle, result *verify.VerificationResult, err error) *VerificationReport {
+	report := &VerificationReport{SchemaVersion: ReportSchemaVersion}
+	if err != nil {
+		report.Error = &ReportError{Kind: ErrorKind(err), Message: err.Error()}
+	}
+	if result == nil {
+		return report
+	}
+
+	report.Verified = err == nil
+	report.MediaType = result.MediaType
+
+	if sig := result.Signature; sig != nil {
+		report.Signer = &ReportSigner{}
+		if sig.PublicKeyID != nil {
+			report.Signer.KeyID = string(*sig.PublicKeyID)
+		}
+		if cert := sig.Certificate; cert != nil {
+			report.Signer.Identity = cert.SubjectAlternativeName
+			report.Signer.Issuer = cert.Issuer
+			report.Signer.CertificateIssuer = cert.CertificateIssuer
+			report.Signer.Extensions = &cert.Extensions
+		}
+		report.Signer.MatchedIdentity = MatchedIdentity(result)
+	}
+
+	for _, ts := range result.VerifiedTimestamps {
+		report.Timestamps = append(report.Timestamps, ReportTimestamp{
+			Type: ts.Type, URI: ts.URI, Timestamp: ts.Timestamp.UTC(),
+		})
+	}
+
+	if bndl != nil && bndl.Bundle != nil {
+		for _, entry := range bndl.VerificationMaterial.GetTlogEntries() {
+			report.TlogEntries = append(report.TlogEntries, ReportTlogEntry{
+				LogIndex:       entry.GetLogIndex(),
+				LogID:          hex.EncodeToString(entry.GetLogId().GetKeyId()),
+				IntegratedTime: time.Unix(entry.GetIntegratedTime(), 0).UTC(),
+				Kind:           entry.GetKindVersion().GetKind(),
+				Version:        entry.GetKindVersion().GetVersion(),
+			})
+		}
+	}
+
+	if st := result.Statement; st != nil {
+		report.Statement = &ReportStatement{
+			PredicateType: st.GetPredicateType(),
+			Subjects:      []ReportSubject{},
+		}
+		for _, s := range st.GetSubject() {
+			report.Statement.Subjects = append(report.Statement.Subjects, ReportSubject{
+				Name: s.GetName(), Digest: s.GetDigest(),
+			})
+		}
+	}
+
+	return report
+}
+
+// NewStreamReport builds a report from the results of verifying a stream
+func NewStreamReport(result *StreamVerificationResult) *StreamReport {
+	report := &StreamReport{
+		SchemaVersion: ReportSchemaVersion,
+		Verified:      result.Passed(),
+		Total:         len(result.Results),
+		Failed:        result.Failed(),
+		Results:       []*VerificationReport{},
+	}
+	for _, r := range result.Results {
+		lineReport := NewVerificationReport(r.Bundle, r.Verification, r.Error)
+		lineReport.Line = r.Line
+		report.Results = append(report.Results, lineReport)
+	}
+	return report
+}
+
+// SetSubjectMatch records the artifact to subject matches in the report
+func (r *VerificationReport) SetSubjectMatch(result *SubjectMatchResult) {
+	r.SubjectMatch = &ReportSubjectMatch{
+		Matched:            result.Matched(),
+		Subjects:           []ReportMatchSubject{},
+		UnmatchedArtifacts: result.UnmatchedArtifacts,
+	}
+	for _, s := range result.Subjects {
+		r.SubjectMatch.Subjects = append(r.SubjectMatch.Subjects, ReportMatchSubject{
+			ReportSubject: ReportSubject{Name: s.Subject.GetName(), Digest: s.Subject.GetDigest()},
+			Matched:       s.Matched(),
+			Artifact:      s.Artifact,
+		})
+	}
+}
+
+// ErrorKind classifies a verification error into one of the report error
+// kinds.
+func ErrorKind(err error) string {
+	var pathErr *fs.PathError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrIdentityMismatch):
+		return ErrorKindIdentity
+	case errors.Is(err, ErrMissingTimestamps):
+		return ErrorKindTimestamps
+	case errors.Is(err, ErrSubjectMismatch):
+		return ErrorKindSubject
+	case errors.Is(err, ErrPolicyViolation):
+		return ErrorKindPolicy
+	case errors.Is(err, ErrSignatureVerification):
+		return ErrorKindSignature
+	case errors.As(err, &pathErr), errors.As(err, &urlErr), errors.As(err, &netErr):
+		return ErrorKindIO
+	default:
+		return ErrorKindOther
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+)
+
+func TestSigstoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	instance := sigstoretest.New(t)
+	blob := []byte("Hello world\n")
+
+	newVerifier := func(t *testing.T, san string) *Verifier {
+		t.Helper()
+		verifier := newTestVerifier(t, instance)
+		verifier.Options.ExpectedIssuer = "https://issuer.example.com"
+		verifier.Options.ExpectedSan = san
+		return verifier
+	}
+
+	for _, tc := range []struct {
+		name     string
+		sign     func(*Signer) ([]byte, error)
+		artifact bool
+	}{
+		{"statement", func(s *Signer) ([]byte, error) {
+			b, err := s.SignStatementContext(t.Context(), testStatement)
+			if err != nil {
+				return nil, err
+			}
+			return protojson.Marshal(b)
+		}, false},
+		{"blob", func(s *Signer) ([]byte, error) {
+			b, err := s.SignBlobContext(t.Context(), blob)
+			if err != nil {
+				return nil, err
+			}
+			return protojson.Marshal(b)
+		}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := tc.sign(newTestSigner(t, instance, emailClaims("signer@example.com")))
+			require.NoError(t, err)
+
+			// Blobs are verified against the digest of the signed data
+			verifierFor := func(san string) *Verifier {
+				v := newVerifier(t, san)
+				if tc.artifact {
+					sum := sha256.Sum256(blob)
+					v.Options.ArtifactDigestAlgo = "sha256"
+					v.Options.ArtifactDigest = hex.EncodeToString(sum[:])
+				}
+				return v
+			}
+
+			verifier := verifierFor("signer@example.com")
+			res, err := verifier.VerifyInlineBundleContext(t.Context(), data)
+			require.NoError(t, err)
+			require.Equal(t, "signer@example.com", res.Signature.Certificate.SubjectAlternativeName)
+			require.Len(t, res.VerifiedTimestamps, 2)
+
+			report := NewVerificationReport(nil, res, nil)
+			require.True(t, report.Verified)
+			require.Equal(t, "signer@example.com", report.Signer.Identity)
+			require.Equal(t, "https://issuer.example.com", report.Signer.Extensions.Issuer)
+			require.Len(t, report.Timestamps, 2)
+			require.Equal(t, tc.artifact, report.Statement == nil)
+
+			// The signer identity must match
+			_, err = verifierFor("other@example.com").VerifyInlineBundleContext(t.Context(), data)
+			require.ErrorIs(t, err, ErrIdentityMismatch)
+			require.Equal(t, ErrorKindIdentity, NewVerificationReport(nil, nil, err).Error.Kind)
+
+			// Any of the expected identities is accepted
+			verifier = verifierFor("other@example.com")
+			verifier.Options.ExpectedIdentities = []ExpectedIdentity{
+				{Issuer: "https://issuer.example.com", SanRegex: "^signer@"},
+			}
+			res, err = verifier.VerifyInlineBundleContext(t.Context(), data)
+			require.NoError(t, err)
+			require.Equal(t, &ExpectedIdentity{Issuer: "https://issuer.example.com", SanRegex: "^signer@"}, MatchedIdentity(res))
+			require.Equal(t, "^signer@", NewVerificationReport(nil, res, nil).Signer.MatchedIdentity.SanRegex)
+
+			// Message signatures are not verified without the artifact
+			if tc.artifact {
+				_, err = newVerifier(t, "signer@example.com").VerifyInlineBundleContext(t.Context(), data)
+				require.ErrorIs(t, err, ErrArtifactRequired)
+			}
+		})
+	}
+}
+
+func TestCertificateExtensions(t *testing.T) {
+	t.Parallel()
+	instance := sigstoretest.New(t)
+
+	// Sign with a token carrying the claims of a GitHub Actions workflow
+	workflow := "example/repo/.github/workflows/release.yml@refs/heads/main"
+	data := signTestStatement(t, instance, map[string]any{
+		"sub":                "repo:example/repo:ref:refs/heads/main",
+		"job_workflow_ref":   workflow,
+		"repository":         "example/repo",
+		"ref":                "refs/heads/main",
+		"sha":                strings.Repeat("b", 40),
+		"event_name":         "push",
+		"runner_environment": "github-hosted",
+	})
+
+	for _, tc := range []struct {
+		name       string
+		extensions certificate.Extensions
+		identity   bool
+		mustErr    bool
+	}{
+		{"none", certificate.Extensions{}, true, false},
+		{
+			"match", certificate.Extensions{
+				SourceRepositoryURI: "https://github.com/example/repo", SourceRepositoryRef: "refs/heads/main",
+				SourceRepositoryDigest: strings.Repeat("b", 40), BuildSignerURI: "https://github.com/" + workflow,
+				BuildTrigger: "push", RunnerEnvironment: "github-hosted",
+			}, true, false,
+		},
+		{"repository", certificate.Extensions{SourceRepositoryURI: "https://github.com/example/other"}, true, true},
+		{"ref", certificate.Extensions{SourceRepositoryRef: "refs/heads/dev"}, true, true},
+		{"runner", certificate.Extensions{RunnerEnvironment: "self-hosted"}, true, true},
+		{"no-identity", certificate.Extensions{BuildTrigger: "push"}, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := newTestVerifier(t, instance)
+			verifier.Options.ExpectedExtensions = tc.extensions
+			if tc.identity {
+				verifier.Options.ExpectedIssuer = "https://issuer.example.com"
+				verifier.Options.ExpectedSanRegex = "^repo:example/repo:"
+			} else {
+				verifier.Options.SkipIdentityCheck = true
+			}
+			_, err := verifier.VerifyInlineBundleContext(t.Context(), data)
+			if !tc.mustErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tc.identity {
+				require.ErrorIs(t, err, ErrIdentityMismatch)
+			}
+		})
+	}
+}
@@ -4,10 +4,13 @@
 package bnd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/sigstore/sigstore-go/pkg/sign"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -15,8 +18,7 @@ const GitHubTimeStamperURL = "https://timestamp.githubapp.com/api/v1/timestamp"
 
 func NewSigner() *Signer {
 	return &Signer{
-		Options:      DefaultSignerOptions,
-		bundleSigner: &bundleSigner{},
+		Options: DefaultSignerOptions,
 	}
 }
 
@@ -25,8 +27,28 @@ type Signer struct {
 	bundleSigner BundleSigner
 }
 
+// getBundleSigner returns the signer implementation. If none was set, it
+// picks the key signer when a key is configured or the keyless one if not.
+func (s *Signer) getBundleSigner() BundleSigner {
+	if s.bundleSigner != nil {
+		return s.bundleSigner
+	}
+	if s.Options.KeyPath != "" {
+		return &keyBundleSigner{}
+	}
+	return &bundleSigner{}
+}
+
 // WriteBundle writes the bundle JSON to
 func (s *Signer) WriteBundle(bndl *v1.Bundle, w io.Writer) error {
+	if s.Options.BundleVersion != "" {
+		var err error
+		bndl, err = ConvertBundle(bndl, s.Options.BundleVersion)
+		if err != nil {
+			return fmt.Errorf("converting bundle: %w", err)
+		}
+	}
+
 	bundleJSON, err := protojson.Marshal(bndl)
 	if err != nil {
 		return fmt.Errorf("marshaling bundle: %w", err)
@@ -40,53 +62,129 @@ func (s *Signer) WriteBundle(bndl *v1.Bundle, w io.Writer) error {
 }
 
 // SignStatement signs a statement using the configured options and
-// returns a bundle. The signing process will try to obtain the
+// returns a bundle. If a signing key is configured, the statement is signed
+// with it. Otherwise, the signing process will try to obtain the
 // signer identity in this order:
 //
-//  1. Try the configured ambient credentials providers
-//     (currently only the GitHub actions plugin is supported).
-//  2. If a terminal is detected, it will start the sigstore oidc
+//  1. Use the identity token set in the options, if any.
+//  2. Try the configured ambient credentials providers.
+//  3. If a terminal is detected, it will start the sigstore oidc
 //     flow in a browser.
-//  3. If no terminal is detected, it will start the sigstore device
+//  4. If no terminal is detected, it will start the sigstore device
 //     flow.
 func (s *Signer) SignStatement(data []byte) (*v1.Bundle, error) {
+	return s.SignStatementContext(context.Background(), data)
+}
+
+// SignStatementContext is SignStatement with a context to cancel the
+// signing process and its network operations.
+func (s *Signer) SignStatementContext(ctx context.Context, data []byte) (*v1.Bundle, error) {
+	bundles, err := s.SignStatementsContext(ctx, [][]byte{data})
+	if err != nil {
+		return nil, err
+	}
+	return bundles[0], nil
+}
+
+// SignStatements signs a set of statements with a single identity. The
+// identity token and keypair are obtained once and, when signing keyless,
+// the Fulcio certificate is reused for all statements while it is valid.
+// The returned bundles are in the same order as the statements.
+func (s *Signer) SignStatements(statements [][]byte) ([]*v1.Bundle, error) {
+	return s.SignStatementsContext(context.Background(), statements)
+}
+
+// SignStatementsContext is SignStatements with a context to cancel the
+// signing process and its network operations.
+func (s *Signer) SignStatementsContext(ctx context.Context, statements [][]byte) ([]*v1.Bundle, error) {
 	// Verify the defined options:
 	if err := s.Options.Validate(); err != nil {
 		return nil, err
 	}
-	// check that statement is not empty and it is an intoto attestation
-	if err := s.bundleSigner.VerifyContent(&s.Options, data); err != nil {
-		return nil, fmt.Errorf("verifying content: %w", err)
+
+	if len(statements) == 0 {
+		return nil, errors.New("no statements to sign")
 	}
 
-	// Wrap the attestation in its DSSE envelope
-	content := s.bundleSigner.WrapStatement(data)
+	bundleSigner := s.getBundleSigner()
+
+	// check that statements are not empty and are intoto attestations,
+	// then wrap each of them in its DSSE envelope
+	contents := make([]sign.Content, 0, len(statements))
+	for i, data := range statements {
+		if err := bundleSigner.VerifyContent(&s.Options, data); err != nil {
+			if len(statements) > 1 {
+				return nil, fmt.Errorf("verifying content of statement #%d: %w", i, err)
+			}
+			return nil, fmt.Errorf("verifying content: %w", err)
+		}
+		contents = append(contents, bundleSigner.WrapStatement(&s.Options, data))
+	}
 
+	return s.signContents(ctx, bundleSigner, contents)
+}
+
+// SignBlob signs arbitrary data and returns a bundle with a message
+// signature. The signer identity is obtained as when signing statements.
+func (s *Signer) SignBlob(data []byte) (*v1.Bundle, error) {
+	return s.SignBlobContext(context.Background(), data)
+}
+
+// SignBlobContext is SignBlob with a context to cancel the signing process
+// and its network operations.
+func (s *Signer) SignBlobContext(ctx context.Context, data []byte) (*v1.Bundle, error) {
+	if err := s.Options.Validate(); err != nil {
+		return nil, err
+	}
+
+	bundles, err := s.signContents(ctx, s.getBundleSigner(), []sign.Content{
+		&sign.PlainData{Data: data},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundles[0], nil
+}
+
+// signContents obtains the signer identity and signs the contents with it
+func (s *Signer) signContents(ctx context.Context, bundleSigner BundleSigner, contents []sign.Content) ([]*v1.Bundle, error) {
 	// Get(or generate) the public key
-	keypair, err := s.bundleSigner.GetKeyPair(&s.Options)
+	keypair, err := bundleSigner.GetKeyPair(&s.Options)
 	if err != nil {
 		return nil, err
 	}
 
 	// Run the STS providers to check for ambien credentials
-	if err := s.bundleSigner.GetAmbienTokens(&s.Options); err != nil {
+	if err := bundleSigner.GetAmbienTokens(ctx, &s.Options); err != nil {
 		return nil, fmt.Errorf("fetching ambien credentials: %w", err)
 	}
 
 	// Get the ID token
-	if err := s.bundleSigner.GetOidcToken(&s.Options); err != nil {
+	if err := bundleSigner.GetOidcToken(ctx, &s.Options); err != nil {
 		return nil, fmt.Errorf("getting ID token: %w", err)
 	}
 
 	// Generate the signer options
-	bundleSignerOption, err := s.bundleSigner.BuildSigstoreSignerOptions(&s.Options)
+	bundleSignerOption, err := bundleSigner.BuildSigstoreSignerOptions(ctx, &s.Options)
 	if err != nil {
 		return nil, fmt.Errorf("building options: %w", err)
 	}
 
-	bndl, err := s.bundleSigner.SignBundle(content, keypair, bundleSignerOption)
-	if err != nil {
-		return nil, fmt.Errorf("singing statement: %w", err)
+	// Reuse the signing certificate across all statements
+	if bundleSignerOption.CertificateProvider != nil && len(contents) > 1 {
+		bundleSignerOption.CertificateProvider = newCachedCertificateProvider(bundleSignerOption.CertificateProvider)
+	}
+
+	bundles := make([]*v1.Bundle, 0, len(contents))
+	for i, content := range contents {
+		bndl, err := bundleSigner.SignBundle(ctx, content, keypair, bundleSignerOption)
+		if err != nil {
+			if len(contents) > 1 {
+				return nil, fmt.Errorf("signing content #%d: %w", i, err)
+			}
+			return nil, fmt.Errorf("signing content: %w", err)
+		}
+		bundles = append(bundles, bndl)
 	}
-	return bndl, nil
+	return bundles, nil
 }
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignStatements(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	statements := [][]byte{}
+	for i := range 3 {
+		statements = append(statements, fmt.Appendf(nil,
+			`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"file%d","digest":{"sha256":"%064d"}}],"predicateType":"https://example.com/test","predicate":{}}`,
+			i, i,
+		))
+	}
+
+	signer := NewSigner()
+	signer.Options.KeyPath = keyPath
+	signer.Options.Timestamp = false
+	signer.Options.AppendToRekor = false
+
+	bundles, err := signer.SignStatements(statements)
+	require.NoError(t, err)
+	require.Len(t, bundles, len(statements))
+	for i, b := range bundles {
+		require.Equal(t, statements[i], b.GetDsseEnvelope().GetPayload())
+	}
+
+	_, err = signer.SignStatements(nil)
+	require.Error(t, err)
+}
+
+func TestSignBlob(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "key.pub")
+	require.NoError(t, os.WriteFile(pubPath, pubPEM, 0o600))
+
+	// Blobs are signed as they are, with no validation
+	data := []byte("not a statement")
+	blobPath := filepath.Join(dir, "blob.txt")
+	require.NoError(t, os.WriteFile(blobPath, data, 0o600))
+
+	signer := NewSigner()
+	signer.Options.KeyPath = keyPath
+	signer.Options.Timestamp = false
+	signer.Options.AppendToRekor = false
+
+	pb, err := signer.SignBlob(data)
+	require.NoError(t, err)
+	require.Nil(t, pb.GetDsseEnvelope())
+	sum := sha512.Sum512(data)
+	require.Equal(t, sum[:], pb.GetMessageSignature().GetMessageDigest().GetDigest())
+
+	bndl, err := bundle.NewBundle(pb)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		artifact string
+		digest   string
+		mustErr  bool
+	}{
+		// Message signatures are not checked without the signed artifact
+		{"no-artifact", "", "", true},
+		{"artifact", blobPath, "", false},
+		// Ed25519 signs the whole message, so the digest is not enough
+		{"digest", "", hex.EncodeToString(sum[:]), true},
+		{"wrong-artifact", keyPath, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefaultVerifierOptions
+			opts.KeyPaths = []string{pubPath}
+			opts.RequireTlog = false
+			opts.RequireTimestamp = false
+			opts.ArtifactPath = tc.artifact
+			opts.ArtifactDigest = tc.digest
+			opts.ArtifactDigestAlgo = "sha512"
+
+			bv := &bundleVerifier{}
+			vrfr, err := bv.BuildSigstoreVerifier(t.Context(), &opts)
+			require.NoError(t, err)
+			_, err = bv.RunVerification(t.Context(), &opts, vrfr, bndl)
+			if tc.artifact == "" && tc.digest == "" {
+				require.ErrorIs(t, err, ErrArtifactRequired)
+				return
+			}
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	trustroot "github.com/sigstore/protobuf-specs/gen/pb-go/trustroot/v1"
+	"github.com/sigstore/sigstore-go/pkg/root"
+)
+
+const (
+	DefaultFulcioURL = "https://fulcio.sigstore.dev"
+	DefaultRekorURL  = "https://rekor.sigstore.dev"
+)
+
+// Major API versions of the sigstore services supported by the signer
+var (
+	supportedFulcioVersions = []uint32{1}
+	supportedRekorVersions  = []uint32{1}
+	supportedTSAVersions    = []uint32{1}
+)
+
+// GetSigningConfig returns the signing configuration to use according to the
+// options. The config is read from a signing_config.json file, fetched from
+// TUF or built from the configured service URLs (defaulting to the sigstore
+// public good instance). Any service URLs set in the options override the
+// corresponding services in the loaded config.
+func GetSigningConfig(opts *SignerOptions) (*root.SigningConfig, error) {
+	return GetSigningConfigContext(context.Background(), opts)
+}
+
+// GetSigningConfigContext is GetSigningConfig with a context to control
+// fetching the config from TUF.
+func GetSigningConfigContext(ctx context.Context, opts *SignerOptions) (*root.SigningConfig, error) {
+	var sc *root.SigningConfig
+	var err error
+	switch {
+	case opts.SigningConfigPath != "":
+		data, err := os.ReadFile(opts.SigningConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading signing config: %w", err)
+		}
+		sc, err = parseSigningConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing signing config from %q: %w", opts.SigningConfigPath, err)
+		}
+	case opts.SigningConfigFromTUF:
+		client, err := GetTufClientContext(ctx, &opts.TufOptions)
+		if err != nil {
+			return nil, fmt.Errorf("creating TUF client: %w", err)
+		}
+		data, err := client.GetTarget("signing_config.json")
+		if err != nil {
+			return nil, fmt.Errorf("fetching signing config from TUF: %w", err)
+		}
+		sc, err = parseSigningConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing signing config from TUF: %w", err)
+		}
+	default:
+		sc, err = defaultSigningConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply the service overrides from the options
+	if opts.FulcioURL != "" {
+		sc = sc.WithFulcioCertificateAuthorityURLs(newService(opts.FulcioURL))
+	}
+	if opts.RekorURL != "" {
+		sc = sc.WithRekorLogURLs(newService(opts.RekorURL))
+	}
+	if opts.TimestampAuthorityURL != "" {
+		sc = sc.WithTimestampAuthorityURLs(newService(opts.TimestampAuthorityURL))
+	}
+
+	return sc, nil
+}
+
+// parseSigningConfig parses signing config JSON data. Missing service
+// selection configurations default to picking any valid service.
+func parseSigningConfig(data []byte) (*root.SigningConfig, error) {
+	pb, err := root.NewSigningConfigProtobuf(data)
+	if err != nil {
+		return nil, err
+	}
+	if pb.GetRekorTlogConfig() == nil {
+		pb.RekorTlogConfig = &trustroot.ServiceConfiguration{Selector: trustroot.ServiceSelector_ANY}
+	}
+	if pb.GetTsaConfig() == nil {
+		pb.TsaConfig = &trustroot.ServiceConfiguration{Selector: trustroot.ServiceSelector_ANY}
+	}
+	return root.NewSigningConfigFromProtobuf(pb)
+}
+
+// defaultSigningConfig builds a signing config pointing to the default
+// sigstore services.
+func defaultSigningConfig(opts *SignerOptions) (*root.SigningConfig, error) {
+	oidcIssuer := DefaultSignerOptions.OidcIssuer
+	if opts.OidcIssuer != "" {
+		oidcIssuer = opts.OidcIssuer
+	}
+
+	return root.NewSigningConfig(
+		root.SigningConfigMediaType02,
+		[]root.Service{newService(DefaultFulcioURL)},
+		[]root.Service{newService(oidcIssuer)},
+		[]root.Service{newService(DefaultRekorURL)},
+		root.ServiceConfiguration{Selector: trustroot.ServiceSelector_ANY},
+		[]root.Service{newService(GitHubTimeStamperURL)},
+		root.ServiceConfiguration{Selector: trustroot.ServiceSelector_ANY},
+	)
+}
+
+// newService returns a service definition for a URL valid at any time
+func newService(url string) root.Service {
+	return root.Service{
+		URL:             url,
+		MajorAPIVersion: 1,
+	}
+}
+
+// normalizeServices clears validity bounds set at the unix epoch. These
+// are the result of reading services without an end (or start) date.
+func normalizeServices(services []root.Service) []root.Service {
+	ret := make([]root.Service, 0, len(services))
+	for _, s := range services {
+		if s.ValidityPeriodStart.Unix() == 0 {
+			s.ValidityPeriodStart = time.Time{}
+		}
+		if s.ValidityPeriodEnd.Unix() == 0 {
+			s.ValidityPeriodEnd = time.Time{}
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// SelectFulcioURL returns the Fulcio instance to use from the signing config
+func SelectFulcioURL(sc *root.SigningConfig) (string, error) {
+	u, err := root.SelectService(
+		normalizeServices(sc.FulcioCertificateAuthorityURLs()), supportedFulcioVersions, time.Now(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("selecting Fulcio instance: %w", err)
+	}
+	return u, nil
+}
+
+// SelectRekorURLs returns the transparency logs to use from the signing config
+func SelectRekorURLs(sc *root.SigningConfig) ([]string, error) {
+	urls, err := root.SelectServices(
+		normalizeServices(sc.RekorLogURLs()), sc.RekorLogURLsConfig(), supportedRekorVersions, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting transparency logs: %w", err)
+	}
+	return urls, nil
+}
+
+// SelectTimestampAuthorityURLs returns the TSAs to use from the signing config
+func SelectTimestampAuthorityURLs(sc *root.SigningConfig) ([]string, error) {
+	urls, err := root.SelectServices(
+		normalizeServices(sc.TimestampAuthorityURLs()), sc.TimestampAuthorityURLsConfig(), supportedTSAVersions, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("selecting timestamp authorities: %w", err)
+	}
+	return urls, nil
+}
+
+// ValidateSigningConfig checks that the signing config has valid services
+// (in their validity window and with a supported API version) for all the
+// operations enabled in the options.
+func ValidateSigningConfig(sc *root.SigningConfig, opts *SignerOptions) error {
+	errs := []error{}
+	if opts.KeyPath == "" {
+		if _, err := SelectFulcioURL(sc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.AppendToRekor {
+		if _, err := SelectRekorURLs(sc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.Timestamp {
+		if _, err := SelectTimestampAuthorityURLs(sc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
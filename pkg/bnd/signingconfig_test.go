@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSigningConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeConfig := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(data), 0o600))
+		return p
+	}
+
+	// Services without an end date and no selection configs
+	openEnded := writeConfig("open.json", `{
+		"mediaType": "application/vnd.dev.sigstore.signingconfig.v0.2+json",
+		"caUrls": [{"url": "https://fulcio.example.com", "majorApiVersion": 1, "validFor": {"start": "2023-01-01T00:00:00Z"}}],
+		"rekorTlogUrls": [{"url": "https://rekor.example.com", "majorApiVersion": 1, "validFor": {"start": "2023-01-01T00:00:00Z"}}],
+		"tsaUrls": [{"url": "https://tsa.example.com/api/v1/timestamp", "majorApiVersion": 1, "validFor": {"start": "2023-01-01T00:00:00Z"}}]
+	}`)
+
+	expired := writeConfig("expired.json", `{
+		"mediaType": "application/vnd.dev.sigstore.signingconfig.v0.2+json",
+		"caUrls": [{"url": "https://fulcio.example.com", "majorApiVersion": 1, "validFor": {"start": "2020-01-01T00:00:00Z", "end": "2021-01-01T00:00:00Z"}}],
+		"rekorTlogUrls": [{"url": "https://rekor.example.com", "majorApiVersion": 1}],
+		"rekorTlogConfig": {"selector": "ANY"},
+		"tsaUrls": [{"url": "https://tsa.example.com", "majorApiVersion": 1}],
+		"tsaConfig": {"selector": "ANY"}
+	}`)
+
+	badVersion := writeConfig("version.json", `{
+		"mediaType": "application/vnd.dev.sigstore.signingconfig.v0.2+json",
+		"caUrls": [{"url": "https://fulcio.example.com", "majorApiVersion": 1}],
+		"rekorTlogUrls": [{"url": "https://rekor.example.com", "majorApiVersion": 2}],
+		"tsaUrls": [{"url": "https://tsa.example.com", "majorApiVersion": 1}]
+	}`)
+
+	malformed := writeConfig("malformed.json", `{"mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1"}`)
+
+	for _, tc := range []struct {
+		name        string
+		opts        SignerOptions
+		mustErr     bool
+		invalid     bool
+		expectFulc  string
+		expectRekor string
+		expectTSA   string
+	}{
+		{
+			"defaults", SignerOptions{Timestamp: true, AppendToRekor: true}, false, false,
+			DefaultFulcioURL, DefaultRekorURL, GitHubTimeStamperURL,
+		},
+		{
+			"overrides",
+			SignerOptions{
+				Timestamp: true, AppendToRekor: true, FulcioURL: "https://f.local",
+				RekorURL: "https://r.local", TimestampAuthorityURL: "https://t.local",
+			},
+			false, false, "https://f.local", "https://r.local", "https://t.local",
+		},
+		{
+			"file-open-ended", SignerOptions{SigningConfigPath: openEnded, Timestamp: true, AppendToRekor: true}, false, false,
+			"https://fulcio.example.com", "https://rekor.example.com", "https://tsa.example.com/api/v1/timestamp",
+		},
+		{
+			"file-with-override",
+			SignerOptions{SigningConfigPath: openEnded, Timestamp: true, AppendToRekor: true, RekorURL: "https://r.local"},
+			false, false, "https://fulcio.example.com", "https://r.local", "https://tsa.example.com/api/v1/timestamp",
+		},
+		{"file-expired", SignerOptions{SigningConfigPath: expired, Timestamp: true, AppendToRekor: true}, false, true, "", "", ""},
+		{"file-expired-key", SignerOptions{SigningConfigPath: expired, KeyPath: "key.pem", Timestamp: true, AppendToRekor: true}, false, false, "", "", ""},
+		{"file-bad-version", SignerOptions{SigningConfigPath: badVersion, Timestamp: true, AppendToRekor: true}, false, true, "", "", ""},
+		{"file-bad-version-no-tlog", SignerOptions{SigningConfigPath: badVersion, Timestamp: true}, false, false, "", "", ""},
+		{"file-malformed", SignerOptions{SigningConfigPath: malformed}, true, false, "", "", ""},
+		{"file-missing", SignerOptions{SigningConfigPath: filepath.Join(dir, "nope.json")}, true, false, "", "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sc, err := GetSigningConfig(&tc.opts)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			err = ValidateSigningConfig(sc, &tc.opts)
+			if tc.invalid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectFulc != "" {
+				u, err := SelectFulcioURL(sc)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectFulc, u)
+			}
+			if tc.expectRekor != "" {
+				u, err := SelectRekorURLs(sc)
+				require.NoError(t, err)
+				require.Equal(t, []string{tc.expectRekor}, u)
+			}
+			if tc.expectTSA != "" {
+				u, err := SelectTimestampAuthorityURLs(sc)
+				require.NoError(t, err)
+				require.Equal(t, []string{tc.expectTSA}, u)
+			}
+		})
+	}
+}
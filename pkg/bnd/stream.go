@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+// maxStreamLineSize is the longest line accepted in a jsonl stream
+const maxStreamLineSize = 10 * 1024 * 1024
+
+// StreamResult is the result of verifying a bundle in a jsonl stream
+type StreamResult struct {
+	// Line is the number of the line in the stream, starting at 1
+	Line         int
+	Bundle       *bundle.Bundle
+	Verification *verify.VerificationResult
+	Error        error
+}
+
+// StreamVerificationResult collects the results of verifying all the
+// bundles in a jsonl stream, ordered by line.
+type StreamVerificationResult struct {
+	Results []StreamResult
+}
+
+// Failed returns the number of bundles that failed to verify
+func (svr *StreamVerificationResult) Failed() int {
+	failed := 0
+	for _, r := range svr.Results {
+		if r.Error != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Passed returns true if all the bundles in the stream verified
+func (svr *StreamVerificationResult) Passed() bool {
+	return svr.Failed() == 0
+}
+
+// VerifyStream verifies all the bundles in a jsonl stream
+func (v *Verifier) VerifyStream(r io.Reader) (*StreamVerificationResult, error) {
+	return v.VerifyStreamContext(context.Background(), r)
+}
+
+// VerifyStreamContext verifies all the bundles in a jsonl stream. The
+// trusted material is loaded once and the bundles are verified in parallel.
+// Failures of single bundles are recorded in their results, the returned
+// error is reserved for problems verifying the stream as a whole, including
+// failing to read it to the end.
+func (v *Verifier) VerifyStreamContext(ctx context.Context, r io.Reader) (*StreamVerificationResult, error) {
+	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(ctx, &v.Options)
+	if err != nil {
+		return nil, fmt.Errorf("creating verifier: %w", err)
+	}
+
+	workers := v.Options.StreamWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var (
+		mtx     sync.Mutex
+		wg      sync.WaitGroup
+		results = []StreamResult{}
+		sem     = make(chan struct{}, workers)
+	)
+	record := func(res StreamResult) {
+		mtx.Lock()
+		results = append(results, res)
+		mtx.Unlock()
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	line := 0
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line++
+		res := StreamResult{Line: line}
+
+		// The scanner reuses its buffer, so the bundle is parsed here
+		// before handing it to a worker.
+		res.Bundle = &bundle.Bundle{}
+		if err := res.Bundle.UnmarshalJSON(scanner.Bytes()); err != nil {
+			res.Bundle = nil
+			res.Error = fmt.Errorf("unmarshaling bundle: %w", err)
+			record(res)
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res.Verification, res.Error = v.bundleVerifier.RunVerification(ctx, &v.Options, vrfr, res.Bundle)
+			if res.Error != nil {
+				res.Error = fmt.Errorf("verifying bundle: %w", res.Error)
+			}
+			record(res)
+		}()
+	}
+	wg.Wait()
+
+	// A read error ends the scan early, the results would be incomplete
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading line %d: %w", line+1, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errors.New("no bundles found in stream")
+	}
+
+	slices.SortFunc(results, func(a, b StreamResult) int { return a.Line - b.Line })
+	return &StreamVerificationResult{Results: results}, nil
+}
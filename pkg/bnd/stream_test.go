@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/carabiner-dev/bnd/internal/sigstoretest"
+)
+
+func TestVerifyStream(t *testing.T) {
+	t.Parallel()
+	instance := sigstoretest.New(t)
+
+	// Build a stream with bundles from two signers and an invalid line
+	var stream bytes.Buffer
+	for _, email := range []string{"signer@example.com", "other@example.com", "signer@example.com"} {
+		stream.Write(signTestStatement(t, instance, emailClaims(email)))
+		stream.WriteString("\n")
+	}
+	stream.WriteString("not json\n")
+
+	for _, tc := range []struct {
+		name    string
+		workers int
+	}{
+		{"default-workers", 0},
+		{"one-worker", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := newTestVerifier(t, instance)
+			verifier.Options.ExpectedIssuer = "https://issuer.example.com"
+			verifier.Options.ExpectedSan = "signer@example.com"
+			verifier.Options.StreamWorkers = tc.workers
+
+			res, err := verifier.VerifyStreamContext(t.Context(), bytes.NewReader(stream.Bytes()))
+			require.NoError(t, err)
+			require.Len(t, res.Results, 4)
+			require.False(t, res.Passed())
+			require.Equal(t, 2, res.Failed())
+			for i, r := range res.Results {
+				require.Equal(t, i+1, r.Line)
+			}
+			require.NoError(t, res.Results[0].Error)
+			require.ErrorIs(t, res.Results[1].Error, ErrIdentityMismatch)
+			require.NoError(t, res.Results[2].Error)
+			require.Error(t, res.Results[3].Error)
+
+			report := NewStreamReport(res)
+			require.False(t, report.Verified)
+			require.Equal(t, 4, report.Total)
+			require.Equal(t, 2, report.Failed)
+			require.Equal(t, 2, report.Results[1].Line)
+			require.Equal(t, ErrorKindIdentity, report.Results[1].Error.Kind)
+			require.NotEmpty(t, report.Results[0].TlogEntries)
+		})
+	}
+
+	// Streams that can't be read to the end must not verify
+	readErr := errors.New("read failed")
+	for _, tc := range []struct {
+		name   string
+		reader io.Reader
+		err    error
+	}{
+		{"read-error", io.MultiReader(bytes.NewReader(stream.Bytes()), iotest.ErrReader(readErr)), readErr},
+		{"long-line", io.MultiReader(
+			bytes.NewReader(stream.Bytes()), strings.NewReader(strings.Repeat("a", maxStreamLineSize+1)),
+		), bufio.ErrTooLong},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := newTestVerifier(t, instance)
+			verifier.Options.ExpectedIssuer = "https://issuer.example.com"
+			verifier.Options.ExpectedSan = "signer@example.com"
+			_, err := verifier.VerifyStreamContext(t.Context(), tc.reader)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		verifier := newTestVerifier(t, instance)
+		_, err := verifier.VerifyStreamContext(t.Context(), strings.NewReader(""))
+		require.Error(t, err)
+	})
+}
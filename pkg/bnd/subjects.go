@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carabiner-dev/hasher"
+	intoto "github.com/in-toto/attestation/go/v1"
+)
+
+// subjectAlgorithms are the algorithms used to hash local artifacts when
+// matching them to statement subjects.
+var subjectAlgorithms = []intoto.HashAlgorithm{
+	intoto.AlgorithmSHA256, intoto.AlgorithmSHA384, intoto.AlgorithmSHA512,
+}
+
+// Artifact is a local artifact to match against the statement subjects
+type Artifact struct {
+	// Name identifies the artifact in the results (eg its path)
+	Name   string
+	Digest map[string]string
+}
+
+// SubjectMatch records the artifact that matched a statement subject
+type SubjectMatch struct {
+	Subject  *intoto.ResourceDescriptor
+	Artifact string
+}
+
+// Matched returns true if an artifact matched the subject
+func (sm *SubjectMatch) Matched() bool {
+	return sm.Artifact != ""
+}
+
+// SubjectMatchResult captures the matches of artifacts to the subjects
+// of a statement.
+type SubjectMatchResult struct {
+	Subjects           []SubjectMatch
+	UnmatchedArtifacts []string
+}
+
+// Matched returns true when all the artifacts matched a subject
+func (smr *SubjectMatchResult) Matched() bool {
+	return len(smr.UnmatchedArtifacts) == 0
+}
+
+// HashArtifacts hashes files to match them against statement subjects
+func HashArtifacts(paths []string) ([]Artifact, error) {
+	if len(paths) == 0 {
+		return []Artifact{}, nil
+	}
+	h := hasher.New()
+	h.Options.Algorithms = subjectAlgorithms
+	hashes, err := h.HashFiles(paths)
+	if err != nil {
+		return nil, fmt.Errorf("hashing artifacts: %w", err)
+	}
+
+	ret := make([]Artifact, 0, len(paths))
+	for _, path := range paths {
+		hs := (*hashes)[path]
+		ret = append(ret, Artifact{Name: path, Digest: hs.ToResourceDescriptor().GetDigest()})
+	}
+	return ret, nil
+}
+
+// MatchSubjects matches artifacts to the subjects of a statement. An
+// artifact matches a subject when they share at least one digest algorithm
+// and all the digests of the shared algorithms are equal.
+func MatchSubjects(subjects []*intoto.ResourceDescriptor, artifacts []Artifact) *SubjectMatchResult {
+	result := &SubjectMatchResult{
+		Subjects:           make([]SubjectMatch, len(subjects)),
+		UnmatchedArtifacts: []string{},
+	}
+	for i, s := range subjects {
+		result.Subjects[i].Subject = s
+	}
+
+	for _, artifact := range artifacts {
+		matched := false
+		for i, s := range subjects {
+			if !digestsMatch(s.GetDigest(), artifact.Digest) {
+				continue
+			}
+			matched = true
+			if !result.Subjects[i].Matched() {
+				result.Subjects[i].Artifact = artifact.Name
+			}
+		}
+		if !matched {
+			result.UnmatchedArtifacts = append(result.UnmatchedArtifacts, artifact.Name)
+		}
+	}
+	return result
+}
+
+// digestsMatch compares two digest sets
+func digestsMatch(a, b map[string]string) bool {
+	common := 0
+	for algo, value := range a {
+		other, ok := b[algo]
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(value, other) {
+			return false
+		}
+		common++
+	}
+	return common > 0
+}
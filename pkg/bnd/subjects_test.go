@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	emptySha256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	emptySha512 = "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"
+)
+
+func TestMatchSubjects(t *testing.T) {
+	t.Parallel()
+	subjects := []*intoto.ResourceDescriptor{
+		{Name: "empty", Digest: map[string]string{"sha256": emptySha256, "sha512": emptySha512}},
+		{Name: "other", Digest: map[string]string{"sha256": "abc123"}},
+	}
+	for _, tc := range []struct {
+		name      string
+		artifacts []Artifact
+		matched   bool
+		subjects  []string
+		unmatched []string
+	}{
+		{"no-artifacts", []Artifact{}, true, []string{"", ""}, []string{}},
+		{"match", []Artifact{{"a", map[string]string{"sha256": emptySha256}}}, true, []string{"a", ""}, []string{}},
+		{"match-case", []Artifact{{"a", map[string]string{"sha256": "ABC123"}}}, true, []string{"", "a"}, []string{}},
+		{"all-common-algos", []Artifact{{"a", map[string]string{"sha256": emptySha256, "sha512": "bad"}}}, false, []string{"", ""}, []string{"a"}},
+		{"no-common-algos", []Artifact{{"a", map[string]string{"sha1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"}}}, false, []string{"", ""}, []string{"a"}},
+		{
+			"partial",
+			[]Artifact{{"a", map[string]string{"sha512": emptySha512}}, {"b", map[string]string{"sha256": "def456"}}},
+			false, []string{"a", ""}, []string{"b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res := MatchSubjects(subjects, tc.artifacts)
+			require.Equal(t, tc.matched, res.Matched())
+			require.Equal(t, tc.unmatched, res.UnmatchedArtifacts)
+			require.Len(t, res.Subjects, len(tc.subjects))
+			for i, s := range res.Subjects {
+				require.Equal(t, tc.subjects[i], s.Artifact)
+			}
+		})
+	}
+}
+
+func TestHashArtifacts(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(path, []byte{}, 0o600))
+	artifacts, err := HashArtifacts([]string{path})
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	require.Equal(t, path, artifacts[0].Name)
+	require.Equal(t, emptySha256, artifacts[0].Digest["sha256"])
+	require.Equal(t, emptySha512, artifacts[0].Digest["sha512"])
+}
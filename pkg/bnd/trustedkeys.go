@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// publicKeyExtensions are the file extensions read when loading the trusted
+// keys from a directory.
+var publicKeyExtensions = []string{".pub", ".pem"}
+
+// LoadTrustedKeys reads the public keys from the specified files and those
+// found in dir and returns them indexed by their fingerprint.
+func LoadTrustedKeys(paths []string, dir string) (map[string]*root.ExpiringKey, error) {
+	allPaths := slices.Clone(paths)
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("reading keys directory: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || !slices.Contains(publicKeyExtensions, strings.ToLower(filepath.Ext(e.Name()))) {
+				continue
+			}
+			allPaths = append(allPaths, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	keys := map[string]*root.ExpiringKey{}
+	for _, path := range allPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading public key: %w", err)
+		}
+
+		pub, err := cryptoutils.UnmarshalPEMToPublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key from %q: %w", path, err)
+		}
+
+		verifier, err := signature.LoadDefaultVerifier(pub)
+		if err != nil {
+			return nil, fmt.Errorf("loading verifier for %q: %w", path, err)
+		}
+
+		fp, err := KeyFingerprint(pub)
+		if err != nil {
+			return nil, err
+		}
+
+		// Plain keys don't carry validity information, so they are trusted
+		// without time constraints.
+		keys[fp] = root.NewExpiringKey(verifier, time.Time{}, time.Time{})
+	}
+	return keys, nil
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// writeTestKey generates a P-256 key and writes its private key and public
+// key PEM files to dir. It returns the paths to both files.
+func writeTestKey(t *testing.T, dir, name string) (keyPath, pubPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	pubPath = filepath.Join(dir, name+".pub")
+	writePublicKey(t, pubPath, key.Public())
+	return keyPath, pubPath
+}
+
+func writePublicKey(t *testing.T, path string, pub crypto.PublicKey) {
+	t.Helper()
+	data, err := cryptoutils.MarshalPublicKeyToPEM(pub)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	keysDir := filepath.Join(dir, "keys")
+	require.NoError(t, os.Mkdir(keysDir, 0o700))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	writePublicKey(t, filepath.Join(keysDir, "ec.pub"), ecKey.Public())
+	writePublicKey(t, filepath.Join(keysDir, "ed.PEM"), edPub)
+	// Files with other extensions and subdirectories are skipped
+	writePublicKey(t, filepath.Join(keysDir, "ignored.txt"), otherKey.Public())
+	require.NoError(t, os.Mkdir(filepath.Join(keysDir, "sub.pub"), 0o700))
+
+	otherPath := filepath.Join(dir, "other.key")
+	writePublicKey(t, otherPath, otherKey.Public())
+
+	badDir := filepath.Join(dir, "bad")
+	require.NoError(t, os.Mkdir(badDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(badDir, "bad.pub"), []byte("not a key"), 0o600))
+
+	fingerprint := func(pub crypto.PublicKey) string {
+		fp, err := KeyFingerprint(pub)
+		require.NoError(t, err)
+		return fp
+	}
+
+	for _, tc := range []struct {
+		name    string
+		paths   []string
+		dir     string
+		mustErr bool
+		keys    []string
+	}{
+		{"dir", nil, keysDir, false, []string{fingerprint(ecKey.Public()), fingerprint(edPub)}},
+		{"paths", []string{otherPath}, "", false, []string{fingerprint(otherKey.Public())}},
+		{
+			"paths-and-dir", []string{otherPath}, keysDir, false,
+			[]string{fingerprint(ecKey.Public()), fingerprint(edPub), fingerprint(otherKey.Public())},
+		},
+		{"unparsable-key", nil, badDir, true, nil},
+		{"missing-dir", nil, filepath.Join(dir, "missing"), true, nil},
+		{"missing-key", []string{filepath.Join(dir, "missing.pub")}, "", true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			keys, err := LoadTrustedKeys(tc.paths, tc.dir)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, keys, len(tc.keys))
+			for _, fp := range tc.keys {
+				require.Contains(t, keys, fp)
+			}
+		})
+	}
+}
+
+func TestVerifyTrustedKeys(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	keyPath, pubPath := writeTestKey(t, dir, "signer")
+	_, otherPubPath := writeTestKey(t, dir, "other")
+
+	signer := NewSigner()
+	signer.Options.KeyPath = keyPath
+	signer.Options.Timestamp = false
+	signer.Options.AppendToRekor = false
+	pb, err := signer.SignStatement(testStatement)
+	require.NoError(t, err)
+	data, err := protojson.Marshal(pb)
+	require.NoError(t, err)
+
+	// A directory trusting only the other key
+	otherDir := filepath.Join(dir, "other")
+	require.NoError(t, os.Mkdir(otherDir, 0o700))
+	require.NoError(t, os.Rename(otherPubPath, filepath.Join(otherDir, "other.pub")))
+
+	for _, tc := range []struct {
+		name    string
+		paths   []string
+		dir     string
+		mustErr bool
+	}{
+		{"key", []string{pubPath}, "", false},
+		{"key-dir", nil, dir, false},
+		{"untrusted-key", nil, otherDir, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := NewVerifier()
+			verifier.Options.KeyPaths = tc.paths
+			verifier.Options.KeyDir = tc.dir
+			verifier.Options.RequireTlog = false
+			verifier.Options.RequireTimestamp = false
+			res, err := verifier.VerifyInlineBundleContext(t.Context(), data)
+			if tc.mustErr {
+				require.ErrorIs(t, err, ErrSignatureVerification)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, res.Signature.PublicKeyID)
+		})
+	}
+}
@@ -4,8 +4,12 @@
 package bnd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/sigstore/sigstore-go/pkg/root"
 	"github.com/sigstore/sigstore-go/pkg/tuf"
 	"github.com/theupdateframework/go-tuf/v2/metadata/fetcher"
 )
@@ -14,26 +18,53 @@ const SigstorePublicGoodBaseURL = "https://tuf-repo-cdn.sigstore.dev"
 
 // TufOptions captures the TUF options handled by bind
 type TufOptions struct {
-	Fetcher     fetcher.Fetcher
+	Fetcher fetcher.Fetcher
+
+	// TufRootPath points to a local trusted_root.json file. When set, the
+	// trusted root is read from it and TUF is never contacted.
 	TufRootPath string
 	TufRootURL  string
+
+	// TufInitialRoot is the root.json used to bootstrap the TUF client. If
+	// not set, the embedded sigstore public good root is used.
+	TufInitialRoot []byte
+
+	// TufCachePath is the directory where TUF metadata is cached. If not
+	// set, the sigstore-go default (~/.sigstore/root) is used.
+	TufCachePath string
 }
 
 // GetTufClient returns a TUF client configured with the options
 func GetTufClient(opts *TufOptions) (*tuf.Client, error) {
+	return GetTufClientContext(context.Background(), opts)
+}
+
+// GetTufClientContext returns a TUF client configured with the options. The
+// client stops fetching metadata when the context is canceled and the
+// downloads are bounded by the context deadline.
+func GetTufClientContext(ctx context.Context, opts *TufOptions) (*tuf.Client, error) {
 	// Build the TUF client:
 	tufOpts := tuf.DefaultOptions()
 	tufOpts.RepositoryBaseURL = SigstorePublicGoodBaseURL
-	tufOpts.Fetcher = defaultfetcher()
 
+	var f fetcher.Fetcher = defaultfetcher()
 	if opts.Fetcher != nil {
-		tufOpts.Fetcher = opts.Fetcher
+		f = opts.Fetcher
 	}
+	tufOpts.Fetcher = &contextFetcher{ctx: ctx, fetcher: f}
 
 	if opts.TufRootURL != "" {
 		tufOpts.RepositoryBaseURL = opts.TufRootURL
 	}
 
+	if len(opts.TufInitialRoot) > 0 {
+		tufOpts.Root = opts.TufInitialRoot
+	}
+
+	if opts.TufCachePath != "" {
+		tufOpts.CachePath = opts.TufCachePath
+	}
+
 	client, err := tuf.New(tufOpts)
 	if err != nil {
 		return nil, fmt.Errorf("creating TUF client: %w", err)
@@ -42,9 +73,29 @@ func GetTufClient(opts *TufOptions) (*tuf.Client, error) {
 }
 
 // GetTufRoot fetches the trusted root from the configured URL or from
-// the sigstore public instance.
+// the sigstore public instance. If a local trusted root path is set in
+// the options, the data is read from the file without touching the
+// network. The returned data is always checked to be a valid trusted root.
 func GetTufRoot(opts *TufOptions) ([]byte, error) {
-	client, err := GetTufClient(opts)
+	return GetTufRootContext(context.Background(), opts)
+}
+
+// GetTufRootContext is GetTufRoot with a context to control the TUF
+// network operations.
+func GetTufRootContext(ctx context.Context, opts *TufOptions) ([]byte, error) {
+	if opts.TufRootPath != "" {
+		data, err := os.ReadFile(opts.TufRootPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading trusted root file: %w", err)
+		}
+
+		if _, err := root.NewTrustedRootFromJSON(data); err != nil {
+			return nil, fmt.Errorf("parsing trusted root from %q: %w", opts.TufRootPath, err)
+		}
+		return data, nil
+	}
+
+	client, err := GetTufClientContext(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("creating TUF client: %w", err)
 	}
@@ -54,9 +105,55 @@ func GetTufRoot(opts *TufOptions) ([]byte, error) {
 		return nil, fmt.Errorf("fetching TUF root data: %w", err)
 	}
 
+	if _, err := root.NewTrustedRootFromJSON(data); err != nil {
+		return nil, fmt.Errorf("parsing trusted root from TUF: %w", err)
+	}
+
 	return data, nil
 }
 
+// GetTrustedRoot returns the parsed trusted root read from the configured
+// local file or fetched from TUF.
+func GetTrustedRoot(opts *TufOptions) (*root.TrustedRoot, error) {
+	return GetTrustedRootContext(context.Background(), opts)
+}
+
+// GetTrustedRootContext is GetTrustedRoot with a context to control the
+// TUF network operations.
+func GetTrustedRootContext(ctx context.Context, opts *TufOptions) (*root.TrustedRoot, error) {
+	data, err := GetTufRootContext(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedRoot, err := root.NewTrustedRootFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing trusted root: %w", err)
+	}
+	return trustedRoot, nil
+}
+
+// contextFetcher wraps a TUF fetcher to honor the cancellation and
+// deadline of a context. As the TUF fetcher interface does not take a
+// context, downloads are not started once the context is done and their
+// timeout is capped to the context deadline.
+type contextFetcher struct {
+	ctx     context.Context
+	fetcher fetcher.Fetcher
+}
+
+func (cf *contextFetcher) DownloadFile(urlPath string, maxLength int64, timeout time.Duration) ([]byte, error) {
+	if err := cf.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if deadline, ok := cf.ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return cf.fetcher.DownloadFile(urlPath, maxLength, timeout)
+}
+
 // defaultfetcher returns a default TUF fetcher configured with the bind UA
 func defaultfetcher() fetcher.Fetcher {
 	f := fetcher.DefaultFetcher{}
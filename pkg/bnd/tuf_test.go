@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/testing/data"
+	"github.com/stretchr/testify/require"
+)
+
+// failFetcher is a TUF fetcher that errors on any attempt to use the network
+type failFetcher struct{}
+
+func (f *failFetcher) DownloadFile(string, int64, time.Duration) ([]byte, error) {
+	return nil, errors.New("network access attempted")
+}
+
+func TestGetTufRootFromPath(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	rootJSON, err := data.TrustedRoot(t, "public-good.json").MarshalJSON()
+	require.NoError(t, err)
+	goodPath := filepath.Join(dir, "trusted_root.json")
+	require.NoError(t, os.WriteFile(goodPath, rootJSON, 0o600))
+
+	badPath := filepath.Join(dir, "malformed.json")
+	require.NoError(t, os.WriteFile(badPath, []byte(`{"mediaType": "nope"`), 0o600))
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		mustErr bool
+	}{
+		{"valid", goodPath, false},
+		{"malformed", badPath, true},
+		{"missing", filepath.Join(dir, "missing.json"), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			opts := &TufOptions{
+				TufRootPath: tc.path,
+				TufRootURL:  "https://tuf.invalid",
+				Fetcher:     &failFetcher{},
+			}
+			res, err := GetTrustedRoot(opts)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, res.FulcioCertificateAuthorities())
+		})
+	}
+}
+
+// timeoutFetcher records the timeout passed to the last download
+type timeoutFetcher struct {
+	timeout time.Duration
+}
+
+func (f *timeoutFetcher) DownloadFile(_ string, _ int64, timeout time.Duration) ([]byte, error) {
+	f.timeout = timeout
+	return []byte("data"), nil
+}
+
+func TestContextFetcher(t *testing.T) {
+	t.Parallel()
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		f := &timeoutFetcher{}
+		_, err := (&contextFetcher{ctx: ctx, fetcher: f}).DownloadFile("https://example.com/", 10, time.Minute)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Zero(t, f.timeout)
+	})
+	t.Run("deadline", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+		defer cancel()
+		f := &timeoutFetcher{}
+		_, err := (&contextFetcher{ctx: ctx, fetcher: f}).DownloadFile("https://example.com/", 10, time.Minute)
+		require.NoError(t, err)
+		require.LessOrEqual(t, f.timeout, 10*time.Second)
+		require.Positive(t, f.timeout)
+	})
+	t.Run("no-deadline", func(t *testing.T) {
+		t.Parallel()
+		f := &timeoutFetcher{}
+		_, err := (&contextFetcher{ctx: t.Context(), fetcher: f}).DownloadFile("https://example.com/", 10, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, f.timeout)
+	})
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"regexp"
+	"slices"
+	"strings"
+
+	intoto "github.com/in-toto/attestation/go/v1"
+)
+
+const (
+	StatementTypeV01 = "https://in-toto.io/Statement/v0.1"
+	StatementTypeV1  = intoto.StatementTypeUri
+
+	// InTotoPayloadType is the DSSE payload type of in-toto statements
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// customDigestName matches the names of digest algorithms not known to
+// the in-toto spec.
+var customDigestName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidationError is returned when a statement fails validation. It lists
+// all the problems found in the statement.
+type ValidationError struct {
+	Problems []string
+}
+
+func (ve *ValidationError) Error() string {
+	if len(ve.Problems) == 1 {
+		return "invalid statement: " + ve.Problems[0]
+	}
+	return fmt.Sprintf(
+		"invalid statement, %d problems found:\n  - %s",
+		len(ve.Problems), strings.Join(ve.Problems, "\n  - "),
+	)
+}
+
+// rawStatement is used to decode the statement fields to validate them
+// without losing information about their types.
+type rawStatement struct {
+	Type          *string           `json:"_type"`
+	Subject       []json.RawMessage `json:"subject"`
+	PredicateType *string           `json:"predicateType"`
+	Predicate     json.RawMessage   `json:"predicate"`
+}
+
+type rawSubject struct {
+	Name   string          `json:"name"`
+	URI    string          `json:"uri"`
+	Digest json.RawMessage `json:"digest"`
+}
+
+// ValidatePayload checks the data to be signed according to its DSSE
+// payload type. In-toto statements are fully validated, JSON payload types
+// (application/json or any +json type) must hold a JSON document and any
+// other types are only checked to not be empty.
+func ValidatePayload(payloadType string, data []byte) error {
+	if payloadType == InTotoPayloadType {
+		return ValidateStatement(data)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &ValidationError{Problems: []string{"payload is empty"}}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(payloadType)
+	if err != nil {
+		return fmt.Errorf("parsing payload type: %w", err)
+	}
+	if (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) && !json.Valid(data) {
+		return &ValidationError{Problems: []string{fmt.Sprintf("payload is not valid JSON as required by its type (%s)", payloadType)}}
+	}
+	return nil
+}
+
+// ValidateStatement checks that data is a valid in-toto statement (v0.1 or
+// v1). If the statement is not valid, the returned error is a
+// *ValidationError listing all the problems found.
+func ValidateStatement(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &ValidationError{Problems: []string{"statement is empty"}}
+	}
+
+	var statement rawStatement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return &ValidationError{Problems: []string{fmt.Sprintf("statement is not a valid JSON object: %v", err)}}
+	}
+
+	problems := []string{}
+	switch {
+	case statement.Type == nil:
+		problems = append(problems, "_type is missing")
+	case *statement.Type != StatementTypeV01 && *statement.Type != StatementTypeV1:
+		problems = append(problems, fmt.Sprintf(
+			"_type %q is not a known in-toto statement type (expected %s or %s)",
+			*statement.Type, StatementTypeV01, StatementTypeV1,
+		))
+	}
+	isV01 := statement.Type != nil && *statement.Type == StatementTypeV01
+
+	if len(statement.Subject) == 0 {
+		problems = append(problems, "statement has no subjects")
+	}
+	for i, s := range statement.Subject {
+		for _, p := range validateSubject(s, isV01) {
+			problems = append(problems, fmt.Sprintf("subject #%d: %s", i, p))
+		}
+	}
+
+	if statement.PredicateType == nil || *statement.PredicateType == "" {
+		problems = append(problems, "predicateType is missing")
+	}
+
+	switch {
+	case len(statement.Predicate) == 0 || string(statement.Predicate) == "null":
+		// Predicates are optional in v0.1 statements
+		if !isV01 {
+			problems = append(problems, "predicate is missing")
+		}
+	case statement.Predicate[0] != '{':
+		problems = append(problems, "predicate is not a JSON object")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateSubject checks a subject entry and returns the problems found
+func validateSubject(data json.RawMessage, isV01 bool) []string {
+	var subject rawSubject
+	if err := json.Unmarshal(data, &subject); err != nil {
+		return []string{"subject is not a JSON object"}
+	}
+
+	problems := []string{}
+	if isV01 && subject.Name == "" {
+		problems = append(problems, "name is missing")
+	}
+
+	if len(subject.Digest) == 0 || string(subject.Digest) == "null" {
+		return append(problems, "digest is missing")
+	}
+
+	digests := map[string]string{}
+	if err := json.Unmarshal(subject.Digest, &digests); err != nil {
+		return append(problems, "digest is not a map of algorithm names to strings")
+	}
+
+	if len(digests) == 0 {
+		return append(problems, "digest is empty")
+	}
+
+	algos := make([]string, 0, len(digests))
+	for algo := range digests {
+		algos = append(algos, algo)
+	}
+	slices.Sort(algos)
+
+	for _, algo := range algos {
+		value := digests[algo]
+		known, ok := intoto.HashAlgorithms[algo]
+		if !ok {
+			if !customDigestName.MatchString(algo) {
+				problems = append(problems, fmt.Sprintf("invalid digest algorithm name %q", algo))
+			}
+			if value == "" {
+				problems = append(problems, fmt.Sprintf("%s digest is empty", algo))
+			}
+			continue
+		}
+
+		raw, err := hex.DecodeString(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s digest %q is not hex encoded", algo, value))
+			continue
+		}
+		if len(raw) != known.HexLength() {
+			problems = append(problems, fmt.Sprintf(
+				"%s digest has the wrong length (%d hex characters, expected %d)",
+				algo, len(value), known.HexLength()*2,
+			))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2025 Carabiner Systems, Inc
+// SPDX-License-Identifier: Apache-2.0
+
+package bnd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStatement(t *testing.T) {
+	t.Parallel()
+	sha256 := strings.Repeat("a", 64)
+	for _, tc := range []struct {
+		name     string
+		data     string
+		problems []string
+	}{
+		{
+			"v1", `{"_type":"https://in-toto.io/Statement/v1","subject":[{"digest":{"sha256":"` + sha256 + `"}}],"predicateType":"https://example.com/t","predicate":{}}`,
+			nil,
+		},
+		{
+			"v01-no-predicate", `{"_type":"https://in-toto.io/Statement/v0.1","subject":[{"name":"f","digest":{"sha256":"` + sha256 + `","custom_algo":"x"}}],"predicateType":"https://example.com/t"}`,
+			nil,
+		},
+		{"empty", "  \n", []string{"statement is empty"}},
+		{"not-json", "{", []string{"statement is not a valid JSON object: unexpected end of JSON input"}},
+		{"array", "[]", []string{"statement is not a valid JSON object: json: cannot unmarshal array into Go value of type bnd.rawStatement"}},
+		{
+			"empty-object", `{}`,
+			[]string{"_type is missing", "statement has no subjects", "predicateType is missing", "predicate is missing"},
+		},
+		{
+			"bad-type", `{"_type":"https://in-toto.io/Statement/v2","subject":[{"digest":{"sha256":"` + sha256 + `"}}],"predicateType":"t","predicate":{}}`,
+			[]string{`_type "https://in-toto.io/Statement/v2" is not a known in-toto statement type (expected https://in-toto.io/Statement/v0.1 or https://in-toto.io/Statement/v1)`},
+		},
+		{
+			"bad-subjects",
+			`{"_type":"https://in-toto.io/Statement/v0.1","subject":[
+				{"digest":{"sha256":"xyz","sha1":"abcd","Bad Algo":"1"}},
+				{"name":"b"},
+				{"name":"c","digest":{}},
+				"d"
+			],"predicateType":"t","predicate":[]}`,
+			[]string{
+				"subject #0: name is missing",
+				`subject #0: invalid digest algorithm name "Bad Algo"`,
+				"subject #0: sha1 digest has the wrong length (4 hex characters, expected 40)",
+				`subject #0: sha256 digest "xyz" is not hex encoded`,
+				"subject #1: digest is missing",
+				"subject #2: digest is empty",
+				"subject #3: subject is not a JSON object",
+				"predicate is not a JSON object",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateStatement([]byte(tc.data))
+			if tc.problems == nil {
+				require.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr))
+			require.Equal(t, tc.problems, verr.Problems)
+		})
+	}
+}
+
+func TestVerifyContentSkipValidation(t *testing.T) {
+	t.Parallel()
+	opts := DefaultSignerOptions
+	require.Error(t, (&bundleSigner{}).VerifyContent(&opts, []byte("{}")))
+	opts.SkipValidation = true
+	require.NoError(t, (&bundleSigner{}).VerifyContent(&opts, []byte("{}")))
+}
+
+func TestValidatePayload(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name        string
+		payloadType string
+		data        string
+		mustErr     bool
+	}{
+		{"intoto", InTotoPayloadType, "{}", true},
+		{"json", "application/json", `{"a":1}`, false},
+		{"json-invalid", "application/json", "{", true},
+		{"json-suffix", "application/vnd.example+json; charset=utf-8", "[]", false},
+		{"json-suffix-invalid", "application/vnd.example+json", "nope", true},
+		{"text", "text/plain", "hello", false},
+		{"empty", "text/plain", " \n", true},
+		{"bad-type", "not a type", "hello", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePayload(tc.payloadType, []byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package bnd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sigstore/sigstore-go/pkg/bundle"
@@ -28,17 +29,23 @@ type Verifier struct {
 
 // VerifyBundle verifies a signed bundle containing a dsse envelope
 func (v *Verifier) VerifyBundle(bundlePath string) (*verify.VerificationResult, error) {
+	return v.VerifyBundleContext(context.Background(), bundlePath)
+}
+
+// VerifyBundleContext is VerifyBundle with a context to cancel fetching
+// the trusted material and the verification.
+func (v *Verifier) VerifyBundleContext(ctx context.Context, bundlePath string) (*verify.VerificationResult, error) {
 	bndl, err := v.bundleVerifier.OpenBundle(bundlePath)
 	if err != nil {
 		return nil, fmt.Errorf("opening bundle: %w", err)
 	}
 
-	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(&v.Options)
+	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(ctx, &v.Options)
 	if err != nil {
 		return nil, fmt.Errorf("creating verifier: %w", err)
 	}
 
-	result, err := v.bundleVerifier.RunVerification(&v.Options, vrfr, bndl)
+	result, err := v.bundleVerifier.RunVerification(ctx, &v.Options, vrfr, bndl)
 	if err != nil {
 		return nil, fmt.Errorf("verifying bundle: %w", err)
 	}
@@ -46,8 +53,14 @@ func (v *Verifier) VerifyBundle(bundlePath string) (*verify.VerificationResult,
 	return result, err
 }
 
-// VerifyBundle verifies a signed bundle containing a dsse envelope
+// VerifyInlineBundle verifies a bundle from its JSON data
 func (v *Verifier) VerifyInlineBundle(bundleContents []byte) (*verify.VerificationResult, error) {
+	return v.VerifyInlineBundleContext(context.Background(), bundleContents)
+}
+
+// VerifyInlineBundleContext is VerifyInlineBundle with a context to cancel
+// fetching the trusted material and the verification.
+func (v *Verifier) VerifyInlineBundleContext(ctx context.Context, bundleContents []byte) (*verify.VerificationResult, error) {
 	var bndl bundle.Bundle
 
 	err := bndl.UnmarshalJSON(bundleContents)
@@ -55,12 +68,12 @@ func (v *Verifier) VerifyInlineBundle(bundleContents []byte) (*verify.Verificati
 		return nil, fmt.Errorf("unmarshaling JSON: %w", err)
 	}
 
-	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(&v.Options)
+	vrfr, err := v.bundleVerifier.BuildSigstoreVerifier(ctx, &v.Options)
 	if err != nil {
 		return nil, fmt.Errorf("creating verifier: %w", err)
 	}
 
-	result, err := v.bundleVerifier.RunVerification(&v.Options, vrfr, &bndl)
+	result, err := v.bundleVerifier.RunVerification(ctx, &v.Options, vrfr, &bndl)
 	if err != nil {
 		return nil, fmt.Errorf("verifying bundle: %w", err)
 	}
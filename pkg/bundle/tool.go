@@ -12,7 +12,9 @@ import (
 	ampelb "github.com/carabiner-dev/ampel/pkg/formats/envelope/bundle"
 	"github.com/carabiner-dev/ampel/pkg/formats/statement/intoto"
 	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type Tool struct{}
@@ -21,12 +23,28 @@ func NewTool() *Tool {
 	return &Tool{}
 }
 
-// Parse reades the budle data from reader r and decodes it into
+// Parse reades the budle data from reader r and decodes it into an envelope.
+// Bundles wrapping DSSE payloads other than in-toto statements are returned
+// without a statement, their payload can be read with ExtractPayload.
 func (t *Tool) ParseBundle(r io.Reader) (attestation.Envelope, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle data: %w", err)
+	}
+
 	p := ampelb.Parser{}
-	envelopeSet, err := p.ParseStream(r)
+	envelopeSet, err := p.Parse(data)
 	if err != nil {
-		return nil, fmt.Errorf("parsing bundle: %w", err)
+		if errors.Is(err, attestation.ErrNotCorrectFormat) {
+			return nil, fmt.Errorf("parsing bundle: %w", err)
+		}
+		// Check if the bundle has a payload that is not a statement
+		env := &ampelb.Envelope{}
+		if perr := protojson.Unmarshal(data, &env.Bundle); perr != nil || env.GetDsseEnvelope() == nil ||
+			env.GetDsseEnvelope().GetPayloadType() == sgbundle.IntotoMediaType {
+			return nil, fmt.Errorf("parsing bundle: %w", err)
+		}
+		return env, nil
 	}
 	if len(envelopeSet) == 0 {
 		return nil, fmt.Errorf("no bundles could be extracted from input")
@@ -37,6 +55,16 @@ func (t *Tool) ParseBundle(r io.Reader) (attestation.Envelope, error) {
 	return envelopeSet[0], nil
 }
 
+// ExtractPayload returns the payload type and raw payload of the DSSE
+// envelope in the bundle.
+func (t *Tool) ExtractPayload(envelope attestation.Envelope) (payloadType string, payload []byte, err error) {
+	bndl, ok := envelope.(*ampelb.Envelope)
+	if !ok || bndl.GetDsseEnvelope() == nil {
+		return "", nil, errors.New("envelope has no DSSE payload")
+	}
+	return bndl.GetDsseEnvelope().GetPayloadType(), bndl.GetDsseEnvelope().GetPayload(), nil
+}
+
 // getBundleContentIfDSSE returns the bundle contents if it is wrapped in a DSSE
 // envelope. Returns nil in any other case.
 func getBundleContentIfDSSE(bundle *protobundle.Bundle) *protobundle.Bundle_DsseEnvelope {
@@ -55,6 +83,10 @@ func getBundleContentIfDSSE(bundle *protobundle.Bundle) *protobundle.Bundle_Dsse
 func (t *Tool) ExtractPredicateJSON(envelope attestation.Envelope) ([]byte, error) {
 	statement := envelope.GetStatement()
 	if statement == nil {
+		// Payloads other than statements are returned raw
+		if _, payload, err := t.ExtractPayload(envelope); err == nil {
+			return payload, nil
+		}
 		return nil, fmt.Errorf("no statement found in envelope")
 	}
 